@@ -3,13 +3,18 @@ package main
 import (
 	"context"
 	"flag"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
+	_ "time/tzdata" // validate saturn.co/timezone on images without zoneinfo
 
+	"github.com/saturn/k8s-agent/pkg/cluster"
 	"github.com/saturn/k8s-agent/pkg/config"
+	"github.com/saturn/k8s-agent/pkg/leader"
 	"github.com/saturn/k8s-agent/pkg/monitor"
+	"github.com/saturn/k8s-agent/pkg/outbox"
 	"github.com/saturn/k8s-agent/pkg/watcher"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -19,28 +24,130 @@ import (
 
 var (
 	kubeconfig = flag.String("kubeconfig", "", "Path to kubeconfig file (optional, uses in-cluster config if not provided)")
-	apiKey     = flag.String("api-key", "", "Saturn API key (can also use SATURN_API_KEY env var)")
+	apiKey     = flag.String("api-key", "", "Saturn API key (can also use SATURN_API_KEY env var; prefer --api-key-file, flags show up in ps)")
+	apiKeyFile = flag.String("api-key-file", "", "File containing the Saturn API key, e.g. a mounted Secret; reloaded when it changes")
 	endpoint   = flag.String("endpoint", "https://saturn.co", "Saturn API endpoint")
 	namespace  = flag.String("namespace", "", "Kubernetes namespace to watch (empty = all namespaces)")
 	syncPeriod = flag.Duration("sync-period", 5*time.Minute, "Sync period for full reconciliation")
+	workers    = flag.Int("workers", 2, "Number of CronJobs synced concurrently")
+	refresh    = flag.Duration("monitor-refresh-interval", 0, "How often monitors of unchanged CronJobs are applied again to repair changes made in Saturn (0 = only when a CronJob changes)")
+	configFile = flag.String("config", "", "Path of a YAML configuration file that overrides flags and is reloaded when it changes")
+
+	clusterName  = flag.String("cluster-name", "", "Cluster name used in monitor names and tags (default: derived from the kube-system namespace UID)")
+	labelTags    = flag.String("label-tags", "", "Comma-separated CronJob labels copied into monitor tags as label:value; rename with label=tag (e.g. team,app.kubernetes.io/part-of=app)")
+	nameTemplate = flag.String("name-template", watcher.DefaultNameTemplate, "Go template for monitor names; fields: .Cluster, .Namespace, .Name, .Labels, .Annotations")
+
+	finalizers       = flag.Bool("finalizers", true, "Add a cleanup finalizer to managed CronJobs so monitors are deleted even if the agent misses the delete")
+	removeFinalizers = flag.Bool("remove-finalizers", false, "Remove the agent's finalizer from all watched CronJobs and exit (run before uninstalling)")
+	stopDeployment   = flag.String("stop-deployment", "", "With --remove-finalizers, first scale this agent Deployment in the agent's namespace to zero and wait for its pods to exit, so it cannot add the finalizers back")
+
+	deletionPolicy = flag.String("deletion-policy", watcher.DeletionPolicyDelete, "What happens to the monitor of a deleted CronJob: delete, disable or retain (override per CronJob with saturn.co/deletion-policy)")
+	reportRuns     = flag.Bool("report-runs", false, "Report runs of monitored CronJobs by watching their Jobs and Pods (opt out per CronJob with saturn.co/report-runs: \"false\")")
+
+	autoGraceMin = flag.Duration("auto-grace-min", time.Minute, "Lower bound of grace periods derived with saturn.co/grace-sec: auto")
+	autoGraceMax = flag.Duration("auto-grace-max", 24*time.Hour, "Upper bound of grace periods derived with saturn.co/grace-sec: auto")
+
+	gcInterval    = flag.Duration("gc-interval", time.Hour, "Interval between orphaned monitor GC passes (0 disables GC)")
+	gcGracePeriod = flag.Duration("gc-grace-period", 24*time.Hour, "How long a monitor must stay orphaned before GC acts on it")
+	gcAction      = flag.String("gc-action", watcher.GCActionDisable, "What GC does with orphaned monitors: delete or disable")
+	gcReportOnly  = flag.Bool("gc-report-only", false, "Log orphaned monitors without deleting or disabling them")
+
+	outboxStore     = flag.String("outbox", "none", "Where failed Saturn API operations are kept until they succeed: configmap, file or none")
+	outboxConfigMap = flag.String("outbox-configmap", "saturn-agent-outbox", "Name of the outbox ConfigMap in the agent's namespace (--outbox=configmap)")
+	outboxFile      = flag.String("outbox-file", "/var/lib/saturn-agent/outbox.json", "Path of the outbox file on a persistent volume (--outbox=file)")
+	metricsAddr     = flag.String("metrics-addr", "", "Address serving Prometheus metrics, e.g. :8080 (empty disables)")
+
+	dryRun = flag.Bool("dry-run", false, "Log the changes the agent would make to Saturn and CronJobs, and serve them on /plan of --metrics-addr, without making them")
+
+	leaderElect          = flag.Bool("leader-elect", false, "Enable leader election so only one replica reconciles at a time")
+	leaderElectLease     = flag.String("leader-elect-lease-name", "saturn-agent", "Name of the Lease used for leader election")
+	leaderElectNamespace = flag.String("leader-elect-namespace", "", "Namespace of the leader election Lease (default: the agent's namespace)")
+	leaseDuration        = flag.Duration("leader-elect-lease-duration", 15*time.Second, "Duration standbys wait before taking over an unrenewed Lease")
+	renewDeadline        = flag.Duration("leader-elect-renew-deadline", 10*time.Second, "Duration the leader retries renewing the Lease before giving up")
+	retryPeriod          = flag.Duration("leader-elect-retry-period", 2*time.Second, "Interval between leader election attempts")
+
+	caCert             = flag.String("ca-cert", "", "Path to PEM bundle of additional CA certificates for the Saturn API")
+	clientCert         = flag.String("client-cert", "", "Path to PEM client certificate for mutual TLS")
+	clientKey          = flag.String("client-key", "", "Path to PEM private key for --client-cert")
+	proxyURL           = flag.String("proxy-url", "", "HTTP(S) proxy for Saturn API requests (default: HTTPS_PROXY env var)")
+	insecureSkipVerify = flag.Bool("insecure-skip-verify", false, "Skip TLS certificate verification (lab environments only)")
+)
+
+const (
+	// configReloadInterval is how often the configuration file is checked for changes
+	configReloadInterval = 10 * time.Second
+
+	// apiKeyReloadInterval is how often the API key file is checked for changes
+	apiKeyReloadInterval = 10 * time.Second
+
+	// stopDeploymentTimeout bounds the wait for agent pods to exit before
+	// finalizers are removed
+	stopDeploymentTimeout = 2 * time.Minute
 )
 
 func main() {
 	klog.InitFlags(nil)
-	flag.Parse()
 
-	// Get API key from flag or environment
-	saturnAPIKey := *apiKey
-	if saturnAPIKey == "" {
-		saturnAPIKey = os.Getenv("SATURN_API_KEY")
+	// "saturn-agent plan [flags]" prints the changes a dry run finds and exits
+	planOnly := len(os.Args) > 1 && os.Args[1] == "plan"
+	if planOnly {
+		flag.CommandLine.Parse(os.Args[2:])
+		*dryRun = true
+	} else {
+		flag.Parse()
 	}
-	if saturnAPIKey == "" {
-		klog.Fatal("Saturn API key required (--api-key or SATURN_API_KEY env var)")
+
+	if *workers < 1 {
+		klog.Fatal("--workers must be at least 1")
+	}
+
+	parsedLabelTags, err := watcher.ParseLabelTags(*labelTags)
+	if err != nil {
+		klog.Fatalf("Invalid --label-tags: %v", err)
+	}
+
+	parsedNameTemplate, err := watcher.ParseNameTemplate(*nameTemplate)
+	if err != nil {
+		klog.Fatalf("Invalid --name-template: %v", err)
+	}
+
+	if err := watcher.ValidateDeletionPolicy(*deletionPolicy); err != nil {
+		klog.Fatalf("Invalid --deletion-policy: %v", err)
+	}
+
+	// Flags form the base that the configuration file overrides
+	baseSettings := watcher.Settings{
+		DefaultGraceSec: watcher.DefaultGraceSec,
+		DeletionPolicy:  *deletionPolicy,
+		NameTemplate:    parsedNameTemplate,
+		LabelTags:       parsedLabelTags,
+	}
+
+	settings := &baseSettings
+	if *configFile != "" {
+		file, err := config.LoadFile(*configFile)
+		if err != nil {
+			klog.Fatalf("Failed to load configuration file: %v", err)
+		}
+		if settings, err = watcher.ApplyFile(baseSettings, file); err != nil {
+			klog.Fatalf("Invalid configuration file %s: %v", *configFile, err)
+		}
+	}
+
+	if *autoGraceMin <= 0 || *autoGraceMax < *autoGraceMin {
+		klog.Fatal("--auto-grace-min must be positive and at most --auto-grace-max")
+	}
+
+	if *gcAction != watcher.GCActionDelete && *gcAction != watcher.GCActionDisable {
+		klog.Fatalf("--gc-action must be %q or %q", watcher.GCActionDelete, watcher.GCActionDisable)
+	}
+
+	if *outboxStore != "configmap" && *outboxStore != "file" && *outboxStore != "none" {
+		klog.Fatalf("--outbox must be \"configmap\", \"file\" or \"none\"")
 	}
 
 	// Build Kubernetes config
 	var k8sConfig *rest.Config
-	var err error
 
 	if *kubeconfig != "" {
 		// Out-of-cluster config (for development)
@@ -60,22 +167,151 @@ func main() {
 		klog.Fatalf("Failed to create Kubernetes client: %v", err)
 	}
 
+	if *removeFinalizers {
+		if *stopDeployment != "" {
+			if err := cluster.StopDeployment(context.Background(), clientset, cluster.AgentNamespace(), *stopDeployment, stopDeploymentTimeout); err != nil {
+				klog.Fatalf("Failed to stop the agent: %v", err)
+			}
+		}
+		if err := watcher.RemoveAllFinalizers(context.Background(), clientset, *namespace); err != nil {
+			klog.Fatalf("Failed to remove finalizers: %v", err)
+		}
+		return
+	}
+
+	// Get API key from file, flag or environment
+	var saturnAPIKey, apiKeyName string
+	switch {
+	case *apiKeyFile != "" && *apiKey != "":
+		klog.Fatal("--api-key and --api-key-file are mutually exclusive")
+	case *apiKeyFile != "":
+		saturnAPIKey, err = config.LoadAPIKeyFile(*apiKeyFile)
+		if err != nil {
+			klog.Fatalf("Failed to read API key: %v", err)
+		}
+		apiKeyName = *apiKeyFile
+	case *apiKey != "":
+		saturnAPIKey, apiKeyName = *apiKey, "--api-key"
+	default:
+		saturnAPIKey, apiKeyName = os.Getenv("SATURN_API_KEY"), "SATURN_API_KEY"
+	}
+	if saturnAPIKey == "" {
+		klog.Fatal("Saturn API key required (--api-key-file, --api-key or SATURN_API_KEY env var)")
+	}
+
+	// Identify the cluster so monitors from different clusters stay distinct
+	if *clusterName == "" {
+		*clusterName, err = cluster.DetectName(context.Background(), clientset)
+		if err != nil {
+			klog.Fatalf("Failed to detect cluster name (set --cluster-name): %v", err)
+		}
+	}
+	if err := cluster.ValidateName(*clusterName); err != nil {
+		klog.Fatal(err)
+	}
+
 	// Create Saturn client
 	saturnConfig := &config.Config{
-		APIKey:   saturnAPIKey,
-		Endpoint: *endpoint,
+		APIKey:             saturnAPIKey,
+		APIKeyName:         apiKeyName,
+		Endpoint:           *endpoint,
+		CACertFile:         *caCert,
+		ClientCertFile:     *clientCert,
+		ClientKeyFile:      *clientKey,
+		ProxyURL:           *proxyURL,
+		InsecureSkipVerify: *insecureSkipVerify,
+	}
+
+	if *insecureSkipVerify {
+		klog.Warning("TLS certificate verification is disabled (--insecure-skip-verify)")
 	}
 
 	// Create monitor manager
-	monitorManager := monitor.NewManager(saturnConfig)
+	monitorManager, err := monitor.NewManager(saturnConfig)
+	if err != nil {
+		klog.Fatalf("Failed to create Saturn client: %v", err)
+	}
+
+	if *dryRun {
+		// Nothing is written, so there is nothing to queue, report or
+		// coordinate with the agent that is making the changes
+		*reportRuns = false
+		*outboxStore = "none"
+		*leaderElect = false
+	}
+
+	// Failed operations are persisted so a Saturn outage or agent restart
+	// cannot lose them
+	var operations *outbox.Outbox
+	switch *outboxStore {
+	case "configmap":
+		operations = outbox.New(&outbox.ConfigMapStore{
+			Clientset: clientset,
+			Namespace: cluster.AgentNamespace(),
+			Name:      *outboxConfigMap,
+		})
+	case "file":
+		operations = outbox.New(&outbox.FileStore{Path: *outboxFile})
+	}
 
 	// Create CronJob watcher
-	cronJobWatcher := watcher.NewCronJobWatcher(
-		clientset,
-		*namespace,
-		*syncPeriod,
-		monitorManager,
-	)
+	cronJobWatcher := watcher.NewCronJobWatcher(clientset, monitorManager, watcher.Options{
+		Namespace:              *namespace,
+		SyncPeriod:             *syncPeriod,
+		Workers:                *workers,
+		MonitorRefreshInterval: *refresh,
+		Finalizers:             *finalizers,
+		ClusterName:            *clusterName,
+		Settings:               *settings,
+		ReportRuns:             *reportRuns,
+		AutoGrace: watcher.AutoGraceOptions{
+			Min: *autoGraceMin,
+			Max: *autoGraceMax,
+		},
+		GC: watcher.GCOptions{
+			Interval:    *gcInterval,
+			GracePeriod: *gcGracePeriod,
+			Action:      *gcAction,
+			ReportOnly:  *gcReportOnly,
+		},
+		Outbox: operations,
+		DryRun: *dryRun,
+	})
+
+	if planOnly {
+		plan, err := cronJobWatcher.PlanOnce(context.Background())
+		if plan == nil {
+			klog.Fatalf("Failed to plan: %v", err)
+		}
+		if err := plan.WriteTable(os.Stdout); err != nil {
+			klog.Fatalf("Failed to print plan: %v", err)
+		}
+		if err != nil {
+			// The plan is incomplete
+			klog.Fatalf("Failed to plan some CronJobs: %v", err)
+		}
+		return
+	}
+
+	if *metricsAddr != "" && (operations != nil || *dryRun) {
+		http.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+			if operations != nil {
+				operations.WriteMetrics(w)
+			}
+			if plan := cronJobWatcher.Plan(); plan != nil {
+				plan.WriteMetrics(w)
+			}
+		})
+		if plan := cronJobWatcher.Plan(); plan != nil {
+			http.Handle("/plan", plan)
+		}
+		go func() {
+			if err := http.ListenAndServe(*metricsAddr, nil); err != nil {
+				klog.Fatalf("Metrics server failed: %v", err)
+			}
+		}()
+	}
 
 	// Create context with cancellation
 	ctx, cancel := context.WithCancel(context.Background())
@@ -85,9 +321,58 @@ func main() {
 	klog.Info("Starting Saturn Kubernetes Agent")
 	klog.Infof("Watching namespace: %s (empty = all namespaces)", *namespace)
 	klog.Infof("Sync period: %s", *syncPeriod)
+	klog.Infof("Workers: %d", *workers)
+	klog.Infof("Monitor refresh interval: %s", *refresh)
+	klog.Infof("Finalizers: %t", *finalizers)
+	klog.Infof("Deletion policy: %s", settings.DeletionPolicy)
+	klog.Infof("Report runs: %t", *reportRuns)
+	klog.Infof("Outbox: %s", *outboxStore)
+	klog.Infof("Cluster name: %s", *clusterName)
+	klog.Infof("Leader election: %t", *leaderElect)
+	klog.Infof("Dry run: %t", *dryRun)
 	klog.Infof("Saturn endpoint: %s", *endpoint)
+	klog.Infof("API key: %s", apiKeyName)
 
-	go cronJobWatcher.Run(ctx)
+	// A rotated Secret reaches the mounted file without a restart
+	if *apiKeyFile != "" {
+		go config.WatchAPIKeyFile(ctx, *apiKeyFile, apiKeyReloadInterval, func(key string) {
+			monitorManager.SetAPIKey(key, *apiKeyFile)
+		})
+	}
+
+	if *configFile != "" {
+		klog.Infof("Configuration file: %s", *configFile)
+		go config.WatchFile(ctx, *configFile, configReloadInterval, func(file *config.File) error {
+			updated, err := watcher.ApplyFile(baseSettings, file)
+			if err != nil {
+				return err
+			}
+			cronJobWatcher.UpdateSettings(updated)
+			return nil
+		})
+	}
+
+	if *leaderElect {
+		// Every replica keeps a warm cache; only the leader runs workers
+		go func() {
+			if err := cronJobWatcher.Start(ctx); err != nil {
+				klog.Fatalf("Failed to start CronJob watcher: %v", err)
+			}
+
+			err := leader.Run(ctx, clientset, leader.Config{
+				LeaseName:      *leaderElectLease,
+				LeaseNamespace: *leaderElectNamespace,
+				LeaseDuration:  *leaseDuration,
+				RenewDeadline:  *renewDeadline,
+				RetryPeriod:    *retryPeriod,
+			}, cronJobWatcher.RunWorkers)
+			if err != nil {
+				klog.Fatalf("Leader election failed: %v", err)
+			}
+		}()
+	} else {
+		go cronJobWatcher.Run(ctx)
+	}
 
 	// Wait for interrupt signal
 	sigCh := make(chan os.Signal, 1)
@@ -0,0 +1,69 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+// stopPollInterval is the wait between checks for remaining pods
+const stopPollInterval = 2 * time.Second
+
+// StopDeployment scales a Deployment to zero and waits until all of its pods
+// are gone, so a running agent cannot undo cleanup done after it. A missing
+// Deployment is already stopped.
+func StopDeployment(ctx context.Context, clientset kubernetes.Interface, namespace, name string, timeout time.Duration) error {
+	deployments := clientset.AppsV1().Deployments(namespace)
+
+	deployment, err := deployments.Get(ctx, name, v1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		klog.Infof("Deployment %s/%s not found, nothing to stop", namespace, name)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get Deployment %s/%s: %w", namespace, name, err)
+	}
+
+	selector, err := v1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		return fmt.Errorf("invalid selector on Deployment %s/%s: %w", namespace, name, err)
+	}
+
+	scale, err := deployments.GetScale(ctx, name, v1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get scale of Deployment %s/%s: %w", namespace, name, err)
+	}
+	if scale.Spec.Replicas != 0 {
+		scale.Spec.Replicas = 0
+		if _, err := deployments.UpdateScale(ctx, name, scale, v1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("failed to scale Deployment %s/%s to zero: %w", namespace, name, err)
+		}
+		klog.Infof("Scaled Deployment %s/%s to zero", namespace, name)
+	}
+
+	// Terminating pods still run the agent until they exit
+	err = wait.PollUntilContextTimeout(ctx, stopPollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+		pods, err := clientset.CoreV1().Pods(namespace).List(ctx, v1.ListOptions{LabelSelector: selector.String()})
+		if err != nil {
+			klog.Warningf("Failed to list pods of Deployment %s/%s: %v (retrying)", namespace, name, err)
+			return false, nil
+		}
+		if len(pods.Items) > 0 {
+			klog.V(2).Infof("Waiting for %d pods of Deployment %s/%s to exit", len(pods.Items), namespace, name)
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		return fmt.Errorf("pods of Deployment %s/%s did not exit: %w", namespace, name, err)
+	}
+
+	klog.Infof("Deployment %s/%s stopped", namespace, name)
+	return nil
+}
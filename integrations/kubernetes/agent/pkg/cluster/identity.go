@@ -0,0 +1,37 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes"
+)
+
+// DetectName derives a stable cluster name from the UID of the kube-system
+// namespace, which is created once and never changes for the lifetime of the
+// cluster. The first 8 characters are unique enough to tell clusters apart
+// while keeping monitor names readable.
+func DetectName(ctx context.Context, clientset kubernetes.Interface) (string, error) {
+	ns, err := clientset.CoreV1().Namespaces().Get(ctx, "kube-system", v1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get kube-system namespace: %w", err)
+	}
+
+	uid := strings.ReplaceAll(string(ns.UID), "-", "")
+	if len(uid) > 8 {
+		uid = uid[:8]
+	}
+	return uid, nil
+}
+
+// ValidateName checks that a cluster name can be used in monitor names and
+// ownership tags
+func ValidateName(name string) error {
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		return fmt.Errorf("invalid cluster name %q: %s", name, strings.Join(errs, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package cluster
+
+import (
+	"os"
+	"strings"
+)
+
+const serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+// AgentNamespace returns the namespace the agent runs in, from POD_NAMESPACE
+// or the service account mount
+func AgentNamespace() string {
+	if ns := os.Getenv("POD_NAMESPACE"); ns != "" {
+		return ns
+	}
+	if data, err := os.ReadFile(serviceAccountNamespaceFile); err == nil {
+		if ns := strings.TrimSpace(string(data)); ns != "" {
+			return ns
+		}
+	}
+	return "default"
+}
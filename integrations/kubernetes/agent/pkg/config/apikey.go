@@ -0,0 +1,41 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// LoadAPIKeyFile reads an API key from a file such as a mounted Secret,
+// ignoring surrounding whitespace
+func LoadAPIKeyFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return parseAPIKey(path, data)
+}
+
+func parseAPIKey(path string, data []byte) (string, error) {
+	key := strings.TrimSpace(string(data))
+	if key == "" {
+		return "", fmt.Errorf("API key file %s is empty", path)
+	}
+	return key, nil
+}
+
+// WatchAPIKeyFile checks path every interval until ctx is cancelled and passes
+// each new key to apply. An empty file is ignored so a half-written Secret
+// update cannot replace a working key.
+func WatchAPIKeyFile(ctx context.Context, path string, interval time.Duration, apply func(key string)) {
+	watchFile(ctx, path, interval, "API key file", func(data []byte) error {
+		key, err := parseAPIKey(path, data)
+		if err != nil {
+			return err
+		}
+		apply(key)
+		return nil
+	})
+}
@@ -4,5 +4,15 @@ package config
 type Config struct {
 	APIKey   string
 	Endpoint string
-}
 
+	// APIKeyName says where the API key came from, e.g. a flag or file path.
+	// It is logged in place of the key.
+	APIKeyName string
+
+	// TLS and proxy settings for requests to the Saturn API
+	CACertFile         string
+	ClientCertFile     string
+	ClientKeyFile      string
+	ProxyURL           string
+	InsecureSkipVerify bool
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+)
+
+// File is the agent configuration file. Settings left out keep the values
+// given by flags.
+type File struct {
+	// Namespaces limits monitoring to these namespaces (empty = all);
+	// ExcludeNamespaces are never monitored
+	Namespaces        []string `json:"namespaces,omitempty"`
+	ExcludeNamespaces []string `json:"excludeNamespaces,omitempty"`
+
+	// Selector is a label selector CronJobs must match, e.g. "team in (a,b)"
+	Selector string `json:"selector,omitempty"`
+
+	// Defaults apply to every monitored CronJob
+	Defaults FileDefaults `json:"defaults,omitempty"`
+
+	DeletionPolicy string   `json:"deletionPolicy,omitempty"`
+	NameTemplate   string   `json:"nameTemplate,omitempty"`
+	LabelTags      []string `json:"labelTags,omitempty"`
+}
+
+// FileDefaults are monitor settings used unless a CronJob's annotations say
+// otherwise
+type FileDefaults struct {
+	GraceSec int      `json:"graceSec,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+}
+
+// LoadFile reads and parses a configuration file. Unknown fields are errors
+// so typos do not go unnoticed.
+func LoadFile(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseFile(path, data)
+}
+
+func parseFile(path string, data []byte) (*File, error) {
+	var file File
+	if err := yaml.UnmarshalStrict(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &file, nil
+}
+
+// WatchFile checks path every interval until ctx is cancelled and passes each
+// changed version to apply. A version that cannot be parsed or that apply
+// rejects is logged and skipped, so the configuration in effect stays.
+func WatchFile(ctx context.Context, path string, interval time.Duration, apply func(*File) error) {
+	watchFile(ctx, path, interval, "configuration file", func(data []byte) error {
+		file, err := parseFile(path, data)
+		if err != nil {
+			return err
+		}
+		return apply(file)
+	})
+}
+
+// watchFile polls path and calls onChange with its content whenever it
+// changes. Polling also catches Secret and ConfigMap volumes, which kubelet
+// updates by swapping a symlink. what names the file in logs.
+func watchFile(ctx context.Context, path string, interval time.Duration, what string, onChange func([]byte) error) {
+	last, err := os.ReadFile(path)
+	if err != nil {
+		klog.Errorf("Failed to read %s %s: %v", what, path, err)
+	}
+
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			klog.Errorf("Failed to read %s %s: %v", what, path, err)
+			return
+		}
+		if bytes.Equal(data, last) {
+			return
+		}
+		last = data
+
+		if err := onChange(data); err != nil {
+			klog.Errorf("Ignoring changed %s %s, keeping the current one: %v", what, path, err)
+			return
+		}
+
+		klog.Infof("Applied changed %s %s", what, path)
+	}, interval)
+}
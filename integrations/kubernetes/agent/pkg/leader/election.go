@@ -0,0 +1,86 @@
+package leader
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/saturn/k8s-agent/pkg/cluster"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/klog/v2"
+)
+
+// Config holds the leader election settings
+type Config struct {
+	LeaseName      string
+	LeaseNamespace string
+	LeaseDuration  time.Duration
+	RenewDeadline  time.Duration
+	RetryPeriod    time.Duration
+}
+
+// Run blocks until ctx is cancelled, calling onStartedLeading with a context
+// that lives as long as this replica holds the Lease. Losing the Lease
+// without a shutdown exits the process so no two replicas reconcile at once.
+func Run(ctx context.Context, clientset kubernetes.Interface, cfg Config, onStartedLeading func(ctx context.Context)) error {
+	namespace := cfg.LeaseNamespace
+	if namespace == "" {
+		namespace = cluster.AgentNamespace()
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		return fmt.Errorf("failed to get hostname: %w", err)
+	}
+	identity := hostname + "_" + string(uuid.NewUUID())
+
+	lock := &resourcelock.LeaseLock{
+		LeaseMeta: metav1.ObjectMeta{
+			Name:      cfg.LeaseName,
+			Namespace: namespace,
+		},
+		Client: clientset.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{
+			Identity: identity,
+		},
+	}
+
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		LeaseDuration:   cfg.LeaseDuration,
+		RenewDeadline:   cfg.RenewDeadline,
+		RetryPeriod:     cfg.RetryPeriod,
+		ReleaseOnCancel: true,
+		Name:            cfg.LeaseName,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(ctx context.Context) {
+				klog.Infof("Acquired leadership as %s", identity)
+				onStartedLeading(ctx)
+			},
+			OnStoppedLeading: func() {
+				if ctx.Err() != nil {
+					klog.Info("Released leadership")
+					return
+				}
+				klog.Fatal("Lost leadership, exiting so another replica can take over")
+			},
+			OnNewLeader: func(current string) {
+				if current != identity {
+					klog.Infof("Current leader is %s, standing by", current)
+				}
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create leader elector: %w", err)
+	}
+
+	klog.Infof("Waiting for leadership of Lease %s/%s", namespace, cfg.LeaseName)
+	elector.Run(ctx)
+	return nil
+}
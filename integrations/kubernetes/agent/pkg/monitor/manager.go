@@ -3,10 +3,16 @@ package monitor
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/saturn/k8s-agent/pkg/config"
@@ -19,8 +25,19 @@ type MonitorSpec struct {
 	ScheduleType string
 	CronExpr     string
 	Timezone     *string
+	IntervalSec  int
 	GraceSec     int
 	Tags         []string
+
+	// Output capture and alert routing. Nil leaves the monitor's current
+	// setting unchanged.
+	CaptureOutput   *bool
+	CaptureLimitKb  *int
+	AlertChannelIDs []string
+
+	// IdempotencyKey is sent with create requests so retries of the same
+	// create return the original monitor instead of a duplicate
+	IdempotencyKey string
 }
 
 // Monitor represents a Saturn monitor
@@ -28,142 +45,359 @@ type Monitor struct {
 	ID           string   `json:"id"`
 	Name         string   `json:"name"`
 	ScheduleType string   `json:"scheduleType"`
+	IntervalSec  int      `json:"intervalSec,omitempty"`
 	CronExpr     string   `json:"cronExpr,omitempty"`
 	Timezone     string   `json:"timezone,omitempty"`
 	GraceSec     int      `json:"graceSec"`
 	Tags         []string `json:"tags,omitempty"`
+	Status       string   `json:"status,omitempty"`
+	Token        string   `json:"token,omitempty"`
+
+	CaptureOutput   *bool    `json:"captureOutput,omitempty"`
+	CaptureLimitKb  *int     `json:"captureLimitKb,omitempty"`
+	AlertChannelIDs []string `json:"alertChannelIds,omitempty"`
 }
 
+// Monitor statuses set by the agent
+const (
+	// StatusOK re-enables a monitor
+	StatusOK = "OK"
+
+	// StatusDisabled is the status of a monitor that does not expect runs
+	StatusDisabled = "DISABLED"
+)
+
+var (
+	// ErrNotFound is returned when the monitor does not exist in Saturn
+	ErrNotFound = errors.New("monitor not found")
+
+	// ErrDisabled is returned when pinging a disabled monitor
+	ErrDisabled = errors.New("monitor is disabled")
+)
+
+// listPageSize is the number of monitors requested per page
+const listPageSize = 100
+
 // Manager manages monitors in Saturn
 type Manager struct {
 	config     *config.Config
 	httpClient *http.Client
+
+	// apiKey is replaced when the key is rotated; requests already sent keep
+	// the key they were sent with
+	apiKeyMu   sync.RWMutex
+	apiKey     string
+	apiKeyName string
 }
 
 // NewManager creates a new monitor manager
-func NewManager(cfg *config.Config) *Manager {
+func NewManager(cfg *config.Config) (*Manager, error) {
+	httpClient, err := newHTTPClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Manager{
-		config: cfg,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		config:     cfg,
+		httpClient: httpClient,
+		apiKey:     cfg.APIKey,
+		apiKeyName: cfg.APIKeyName,
+	}, nil
+}
+
+// SetAPIKey replaces the API key used for new requests. name identifies where
+// the key came from and is the only part of it that is logged.
+func (m *Manager) SetAPIKey(key, name string) {
+	m.apiKeyMu.Lock()
+	m.apiKey = key
+	m.apiKeyName = name
+	m.apiKeyMu.Unlock()
+
+	klog.Infof("Using Saturn API key from %s (fingerprint %s)", name, fingerprint(key))
+}
+
+// do sends an authenticated API request. A rejected key is logged by name
+// and fingerprint, never by value.
+func (m *Manager) do(req *http.Request) (*http.Response, error) {
+	m.apiKeyMu.RLock()
+	key, name := m.apiKey, m.apiKeyName
+	m.apiKeyMu.RUnlock()
+
+	req.Header.Set("Authorization", "Bearer "+key)
+
+	resp, err := m.httpClient.Do(req)
+	if err == nil && resp.StatusCode == http.StatusUnauthorized {
+		klog.Errorf("Saturn rejected the API key from %s (fingerprint %s) with 401 Unauthorized; check that the key exists and has not been revoked", name, fingerprint(key))
 	}
+	return resp, err
 }
 
-// CreateMonitor creates a new monitor in Saturn
-func (m *Manager) CreateMonitor(ctx context.Context, spec *MonitorSpec) (string, error) {
+// fingerprint returns a short hash that tells keys apart without revealing them
+func fingerprint(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:4])
+}
+
+// newMonitor builds the API request body for spec
+func newMonitor(spec *MonitorSpec) *Monitor {
 	monitor := &Monitor{
-		Name:         spec.Name,
-		ScheduleType: spec.ScheduleType,
-		CronExpr:     spec.CronExpr,
-		GraceSec:     spec.GraceSec,
-		Tags:         spec.Tags,
+		Name:            spec.Name,
+		ScheduleType:    spec.ScheduleType,
+		IntervalSec:     spec.IntervalSec,
+		CronExpr:        spec.CronExpr,
+		GraceSec:        spec.GraceSec,
+		Tags:            spec.Tags,
+		CaptureOutput:   spec.CaptureOutput,
+		CaptureLimitKb:  spec.CaptureLimitKb,
+		AlertChannelIDs: spec.AlertChannelIDs,
 	}
 
 	if spec.Timezone != nil {
 		monitor.Timezone = *spec.Timezone
 	}
 
-	body, err := json.Marshal(monitor)
+	return monitor
+}
+
+// CreateMonitor creates a new monitor in Saturn
+func (m *Manager) CreateMonitor(ctx context.Context, spec *MonitorSpec) (*Monitor, error) {
+	body, err := json.Marshal(newMonitor(spec))
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal monitor: %w", err)
+		return nil, fmt.Errorf("failed to marshal monitor: %w", err)
 	}
 
 	req, err := http.NewRequestWithContext(ctx, "POST", m.config.Endpoint+"/api/monitors", bytes.NewReader(body))
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("Authorization", "Bearer "+m.config.APIKey)
 	req.Header.Set("Content-Type", "application/json")
+	if spec.IdempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", spec.IdempotencyKey)
+	}
 
-	resp, err := m.httpClient.Do(req)
+	resp, err := m.do(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to send request: %w", err)
+		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("failed to read response: %w", err)
+		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return "", fmt.Errorf("API error %d: %s", resp.StatusCode, string(respBody))
+		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(respBody))
 	}
 
 	var created Monitor
 	if err := json.Unmarshal(respBody, &created); err != nil {
-		return "", fmt.Errorf("failed to unmarshal response: %w", err)
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 
 	klog.V(2).Infof("Created monitor %s: %s", created.ID, created.Name)
-	return created.ID, nil
+	return &created, nil
 }
 
-// UpdateMonitor updates an existing monitor
-func (m *Manager) UpdateMonitor(ctx context.Context, id string, spec *MonitorSpec) error {
-	monitor := &Monitor{
-		Name:         spec.Name,
-		ScheduleType: spec.ScheduleType,
-		CronExpr:     spec.CronExpr,
-		GraceSec:     spec.GraceSec,
-		Tags:         spec.Tags,
-	}
-
-	if spec.Timezone != nil {
-		monitor.Timezone = *spec.Timezone
-	}
-
-	body, err := json.Marshal(monitor)
+// UpdateMonitor updates an existing monitor and returns its new state
+func (m *Manager) UpdateMonitor(ctx context.Context, id string, spec *MonitorSpec) (*Monitor, error) {
+	updated, err := m.patchMonitor(ctx, id, newMonitor(spec))
 	if err != nil {
-		return fmt.Errorf("failed to marshal monitor: %w", err)
+		return nil, err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "PATCH", m.config.Endpoint+"/api/monitors/"+id, bytes.NewReader(body))
+	klog.V(2).Infof("Updated monitor %s", id)
+	return updated, nil
+}
+
+// DeleteMonitor deletes a monitor
+func (m *Manager) DeleteMonitor(ctx context.Context, id string) error {
+	req, err := http.NewRequestWithContext(ctx, "DELETE", m.config.Endpoint+"/api/monitors/"+id, nil)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("Authorization", "Bearer "+m.config.APIKey)
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := m.httpClient.Do(req)
+	resp, err := m.do(req)
 	if err != nil {
 		return fmt.Errorf("failed to send request: %w", err)
 	}
 	defer resp.Body.Close()
 
+	// Already gone, e.g. cleaned up by the finalizer before the delete event
+	if resp.StatusCode == http.StatusNotFound {
+		klog.V(2).Infof("Monitor %s already deleted", id)
+		return nil
+	}
+
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		respBody, _ := io.ReadAll(resp.Body)
 		return fmt.Errorf("API error %d: %s", resp.StatusCode, string(respBody))
 	}
 
-	klog.V(2).Infof("Updated monitor %s", id)
+	klog.V(2).Infof("Deleted monitor %s", id)
 	return nil
 }
 
-// DeleteMonitor deletes a monitor
-func (m *Manager) DeleteMonitor(ctx context.Context, id string) error {
-	req, err := http.NewRequestWithContext(ctx, "DELETE", m.config.Endpoint+"/api/monitors/"+id, nil)
+// ListMonitors returns all monitors carrying tag, following pagination
+func (m *Manager) ListMonitors(ctx context.Context, tag string) ([]Monitor, error) {
+	var all []Monitor
+	cursor := ""
+
+	for {
+		query := url.Values{}
+		query.Set("tag", tag)
+		query.Set("limit", strconv.Itoa(listPageSize))
+		if cursor != "" {
+			query.Set("cursor", cursor)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "GET", m.config.Endpoint+"/api/monitors?"+query.Encode(), nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		resp, err := m.do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to send request: %w", err)
+		}
+
+		respBody, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response: %w", err)
+		}
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(respBody))
+		}
+
+		var page struct {
+			Monitors   []Monitor `json:"monitors"`
+			NextCursor string    `json:"nextCursor"`
+		}
+		if err := json.Unmarshal(respBody, &page); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+		}
+
+		all = append(all, page.Monitors...)
+
+		if page.NextCursor == "" || len(page.Monitors) == 0 {
+			return all, nil
+		}
+		cursor = page.NextCursor
+	}
+}
+
+// DisableMonitor sets a monitor's status to DISABLED, keeping its history
+func (m *Manager) DisableMonitor(ctx context.Context, id string) error {
+	if _, err := m.patchMonitor(ctx, id, map[string]string{"status": StatusDisabled}); err != nil {
+		return err
+	}
+
+	klog.V(2).Infof("Disabled monitor %s", id)
+	return nil
+}
+
+// EnableMonitor re-enables a disabled monitor. A non-zero nextDueAt replaces
+// the expected time of the next run so the time spent disabled does not
+// count as missed runs.
+func (m *Manager) EnableMonitor(ctx context.Context, id string, nextDueAt time.Time) error {
+	fields := map[string]string{"status": StatusOK}
+	if !nextDueAt.IsZero() {
+		fields["nextDueAt"] = nextDueAt.UTC().Format(time.RFC3339)
+	}
+
+	if _, err := m.patchMonitor(ctx, id, fields); err != nil {
+		return err
+	}
+
+	klog.V(2).Infof("Enabled monitor %s (next run due %s)", id, nextDueAt)
+	return nil
+}
+
+// SetMonitorTags replaces the tags of a monitor, leaving its other settings
+// unchanged
+func (m *Manager) SetMonitorTags(ctx context.Context, id string, tags []string) error {
+	if _, err := m.patchMonitor(ctx, id, map[string][]string{"tags": tags}); err != nil {
+		return err
+	}
+
+	klog.V(2).Infof("Updated tags of monitor %s", id)
+	return nil
+}
+
+// patchMonitor sends a partial update and returns the monitor from the response
+func (m *Manager) patchMonitor(ctx context.Context, id string, fields interface{}) (*Monitor, error) {
+	body, err := json.Marshal(fields)
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to marshal monitor: %w", err)
 	}
 
-	req.Header.Set("Authorization", "Bearer "+m.config.APIKey)
+	req, err := http.NewRequestWithContext(ctx, "PATCH", m.config.Endpoint+"/api/monitors/"+id, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
 
-	resp, err := m.httpClient.Do(req)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := m.do(req)
 	if err != nil {
-		return fmt.Errorf("failed to send request: %w", err)
+		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
 	defer resp.Body.Close()
 
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("monitor %s: %w", id, ErrNotFound)
+	}
+
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		respBody, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API error %d: %s", resp.StatusCode, string(respBody))
+		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(respBody))
 	}
 
-	klog.V(2).Infof("Deleted monitor %s", id)
-	return nil
+	updated := &Monitor{ID: id}
+	if len(bytes.TrimSpace(respBody)) > 0 {
+		if err := json.Unmarshal(respBody, updated); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+		}
+	}
+	return updated, nil
 }
 
+// GetMonitor fetches a monitor by ID
+func (m *Manager) GetMonitor(ctx context.Context, id string) (*Monitor, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", m.config.Endpoint+"/api/monitors/"+id, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := m.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("monitor %s: %w", id, ErrNotFound)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var found Monitor
+	if err := json.Unmarshal(respBody, &found); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return &found, nil
+}
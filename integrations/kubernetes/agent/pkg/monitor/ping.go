@@ -0,0 +1,115 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"k8s.io/klog/v2"
+)
+
+// Ping states
+const (
+	PingStart   = "start"
+	PingSuccess = "success"
+	PingFail    = "fail"
+)
+
+// Ping reports one state change of a run
+type Ping struct {
+	State      string
+	ExitCode   *int
+	DurationMs *int64
+
+	// Failure explains a fail ping
+	Failure *Failure
+
+	// Output is uploaded as the run's captured output
+	Output string
+}
+
+// Failure is a structured reason for a failed run
+type Failure struct {
+	// Reason is a short machine-readable cause, e.g. OOMKilled
+	Reason string
+
+	// Message is a human-readable description
+	Message string
+
+	// Container and Node locate the failure, if known
+	Container string
+	Node      string
+}
+
+// maxFailureMessageLen bounds the message sent with a fail ping
+const maxFailureMessageLen = 1000
+
+// SendPing reports a run to the monitor identified by token
+func (m *Manager) SendPing(ctx context.Context, token string, ping *Ping) error {
+	params := url.Values{}
+	params.Set("state", ping.State)
+	if ping.ExitCode != nil {
+		params.Set("exitCode", strconv.Itoa(*ping.ExitCode))
+	}
+	if ping.DurationMs != nil {
+		params.Set("durationMs", strconv.FormatInt(*ping.DurationMs, 10))
+	}
+	if f := ping.Failure; f != nil {
+		setIfNotEmpty(params, "reason", f.Reason)
+		if len(f.Message) > maxFailureMessageLen {
+			setIfNotEmpty(params, "message", f.Message[:maxFailureMessageLen])
+		} else {
+			setIfNotEmpty(params, "message", f.Message)
+		}
+		setIfNotEmpty(params, "container", f.Container)
+		setIfNotEmpty(params, "node", f.Node)
+	}
+
+	pingURL := m.config.Endpoint + "/api/ping/" + url.PathEscape(token) + "?" + params.Encode()
+
+	var req *http.Request
+	var err error
+
+	if ping.Output != "" {
+		req, err = http.NewRequestWithContext(ctx, "POST", pingURL, strings.NewReader(ping.Output))
+		if err != nil {
+			return fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("Content-Type", "text/plain")
+	} else {
+		req, err = http.NewRequestWithContext(ctx, "GET", pingURL, nil)
+		if err != nil {
+			return fmt.Errorf("failed to create request: %w", err)
+		}
+	}
+
+	req.Header.Set("User-Agent", "Saturn-K8s-Agent/1.0")
+
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send ping: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusForbidden {
+		return ErrDisabled
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("ping API returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	klog.V(3).Infof("Sent %s ping", ping.State)
+	return nil
+}
+
+func setIfNotEmpty(params url.Values, key, value string) {
+	if value != "" {
+		params.Set(key, value)
+	}
+}
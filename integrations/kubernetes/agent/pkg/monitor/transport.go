@@ -0,0 +1,103 @@
+package monitor
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/saturn/k8s-agent/pkg/config"
+)
+
+// newHTTPClient builds the HTTP client used for Saturn API requests from the
+// --ca-cert, --client-cert, --client-key, --proxy-url and
+// --insecure-skip-verify settings
+func newHTTPClient(cfg *config.Config) (*http.Client, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+	}
+
+	if cfg.CACertFile != "" {
+		pool, err := loadRootCAs(cfg.CACertFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.ClientCertFile != "" || cfg.ClientKeyFile != "" {
+		cert, err := loadClientCertificate(cfg.ClientCertFile, cfg.ClientKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	// Without --proxy-url the cloned transport honours HTTPS_PROXY/NO_PROXY
+	if cfg.ProxyURL != "" {
+		proxy, err := parseProxyURL(cfg.ProxyURL)
+		if err != nil {
+			return nil, err
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	return &http.Client{
+		Timeout:   30 * time.Second,
+		Transport: transport,
+	}, nil
+}
+
+// loadRootCAs returns the system roots extended with the certificates in path
+func loadRootCAs(path string) (*x509.CertPool, error) {
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("--ca-cert: %w", err)
+	}
+
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("--ca-cert: %s contains no PEM certificates", path)
+	}
+	return pool, nil
+}
+
+// loadClientCertificate loads the key pair for mutual TLS
+func loadClientCertificate(certFile, keyFile string) (tls.Certificate, error) {
+	if certFile == "" || keyFile == "" {
+		return tls.Certificate{}, errors.New("--client-cert requires --client-key and vice versa")
+	}
+
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("--client-cert/--client-key: %w", err)
+	}
+	return cert, nil
+}
+
+// parseProxyURL parses --proxy-url. The error leaves out the value, which may
+// carry the proxy password.
+func parseProxyURL(raw string) (*url.URL, error) {
+	proxy, err := url.Parse(raw)
+	if err == nil {
+		return proxy, nil
+	}
+
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+	return nil, fmt.Errorf("--proxy-url is not a valid URL: %w", err)
+}
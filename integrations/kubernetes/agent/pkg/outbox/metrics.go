@@ -0,0 +1,18 @@
+package outbox
+
+import (
+	"fmt"
+	"io"
+)
+
+// WriteMetrics writes the outbox depth in the Prometheus text format
+func (o *Outbox) WriteMetrics(w io.Writer) {
+	depth, oldest := o.Depth()
+
+	fmt.Fprintln(w, "# HELP saturn_agent_outbox_depth Saturn API operations waiting to be retried.")
+	fmt.Fprintln(w, "# TYPE saturn_agent_outbox_depth gauge")
+	fmt.Fprintf(w, "saturn_agent_outbox_depth %d\n", depth)
+	fmt.Fprintln(w, "# HELP saturn_agent_outbox_oldest_seconds Age of the oldest queued operation.")
+	fmt.Fprintln(w, "# TYPE saturn_agent_outbox_oldest_seconds gauge")
+	fmt.Fprintf(w, "saturn_agent_outbox_oldest_seconds %g\n", oldest.Seconds())
+}
@@ -0,0 +1,179 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// Operation kinds
+const (
+	// KindSync creates or updates the monitor of an existing CronJob
+	KindSync = "sync"
+
+	// KindDelete applies the deletion policy to the monitor of a deleted CronJob
+	KindDelete = "delete"
+)
+
+// Operation is a change for Saturn that has not been applied yet
+type Operation struct {
+	// Key is the CronJob's namespace/name
+	Key  string `json:"key"`
+	Kind string `json:"kind"`
+
+	// UID, MonitorID and DeletionPolicy describe a deleted CronJob and its
+	// monitor, which cannot be read from the cluster any more
+	UID            string `json:"uid,omitempty"`
+	MonitorID      string `json:"monitorId,omitempty"`
+	DeletionPolicy string `json:"deletionPolicy,omitempty"`
+
+	// Seq orders operations for replay
+	Seq      uint64    `json:"seq"`
+	QueuedAt time.Time `json:"queuedAt"`
+
+	// Error is the failure that queued the operation, for troubleshooting
+	Error string `json:"error,omitempty"`
+}
+
+// Store persists the pending operations
+type Store interface {
+	Load(ctx context.Context) ([]Operation, error)
+	Save(ctx context.Context, ops []Operation) error
+}
+
+// Outbox holds at most one pending operation per CronJob and persists every
+// change to its Store
+type Outbox struct {
+	store Store
+
+	mu  sync.Mutex
+	ops map[string]Operation
+	seq uint64
+}
+
+// New creates an empty outbox backed by store
+func New(store Store) *Outbox {
+	return &Outbox{
+		store: store,
+		ops:   make(map[string]Operation),
+	}
+}
+
+// Load replaces the pending operations with those in the store
+func (o *Outbox) Load(ctx context.Context) error {
+	ops, err := o.store.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load outbox: %w", err)
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.ops = make(map[string]Operation, len(ops))
+	o.seq = 0
+	for _, op := range ops {
+		o.ops[op.Key] = op
+		if op.Seq > o.seq {
+			o.seq = op.Seq
+		}
+	}
+
+	klog.Infof("Loaded %d pending operations from the outbox", len(ops))
+	return nil
+}
+
+// Put records op as the pending operation of its CronJob, replacing an
+// earlier one. The CronJob keeps its place in the replay order. Callers keep
+// putting a delete until it has been applied, even after a CronJob with the
+// same name was created, so a sync never replaces a pending delete.
+func (o *Outbox) Put(ctx context.Context, op Operation) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if prev, ok := o.ops[op.Key]; ok {
+		if prev.Kind == op.Kind && prev.UID == op.UID && prev.MonitorID == op.MonitorID && prev.DeletionPolicy == op.DeletionPolicy {
+			return nil // Nothing new to persist
+		}
+		op.Seq = prev.Seq
+		op.QueuedAt = prev.QueuedAt
+	} else {
+		o.seq++
+		op.Seq = o.seq
+		op.QueuedAt = time.Now()
+	}
+
+	o.ops[op.Key] = op
+	if err := o.save(ctx); err != nil {
+		return err
+	}
+
+	klog.V(2).Infof("Queued %s of %s in the outbox (%d pending)", op.Kind, op.Key, len(o.ops))
+	return nil
+}
+
+// Done removes the pending operation of a CronJob once it has been applied
+func (o *Outbox) Done(ctx context.Context, key string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if _, ok := o.ops[key]; !ok {
+		return nil
+	}
+
+	delete(o.ops, key)
+	if err := o.save(ctx); err != nil {
+		return err
+	}
+
+	klog.V(2).Infof("Applied queued operation of %s (%d pending)", key, len(o.ops))
+	return nil
+}
+
+// Pending returns the pending operations in replay order
+func (o *Outbox) Pending() []Operation {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	ops := make([]Operation, 0, len(o.ops))
+	for _, op := range o.ops {
+		ops = append(ops, op)
+	}
+	sort.Slice(ops, func(i, j int) bool { return ops[i].Seq < ops[j].Seq })
+	return ops
+}
+
+// Depth returns the number of pending operations and the age of the oldest
+func (o *Outbox) Depth() (int, time.Duration) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	var oldest time.Time
+	for _, op := range o.ops {
+		if oldest.IsZero() || op.QueuedAt.Before(oldest) {
+			oldest = op.QueuedAt
+		}
+	}
+
+	if oldest.IsZero() {
+		return 0, 0
+	}
+	return len(o.ops), time.Since(oldest)
+}
+
+// save writes all operations to the store; o.mu must be held
+func (o *Outbox) save(ctx context.Context) error {
+	ops := make([]Operation, 0, len(o.ops))
+	for _, op := range o.ops {
+		ops = append(ops, op)
+	}
+	sort.Slice(ops, func(i, j int) bool { return ops[i].Seq < ops[j].Seq })
+
+	if err := o.store.Save(ctx, ops); err != nil {
+		return fmt.Errorf("failed to save outbox: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+)
+
+// configMapKey is the ConfigMap data key holding the operations
+const configMapKey = "operations.json"
+
+// maxConfigMapBytes keeps the outbox below the 1 MiB ConfigMap limit
+const maxConfigMapBytes = 900 * 1024
+
+// FileStore keeps the operations in a JSON file, e.g. on a PersistentVolume
+type FileStore struct {
+	Path string
+}
+
+// Load reads the operations; a missing file is an empty outbox
+func (s *FileStore) Load(ctx context.Context) ([]Operation, error) {
+	data, err := os.ReadFile(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var ops []Operation
+	if err := json.Unmarshal(data, &ops); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", s.Path, err)
+	}
+	return ops, nil
+}
+
+// Save replaces the file atomically so a crash never leaves it half written
+func (s *FileStore) Save(ctx context.Context, ops []Operation) error {
+	data, err := json.Marshal(ops)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.Path), filepath.Base(s.Path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), s.Path)
+}
+
+// ConfigMapStore keeps the operations in a ConfigMap. It needs no volume and
+// survives the agent moving between nodes, but holds less than 1 MiB.
+type ConfigMapStore struct {
+	Clientset kubernetes.Interface
+	Namespace string
+	Name      string
+}
+
+// Load reads the operations; a missing ConfigMap is an empty outbox
+func (s *ConfigMapStore) Load(ctx context.Context) ([]Operation, error) {
+	cm, err := s.Clientset.CoreV1().ConfigMaps(s.Namespace).Get(ctx, s.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	data := cm.Data[configMapKey]
+	if data == "" {
+		return nil, nil
+	}
+
+	var ops []Operation
+	if err := json.Unmarshal([]byte(data), &ops); err != nil {
+		return nil, fmt.Errorf("failed to parse ConfigMap %s/%s: %w", s.Namespace, s.Name, err)
+	}
+	return ops, nil
+}
+
+// Save writes the operations, creating the ConfigMap if needed
+func (s *ConfigMapStore) Save(ctx context.Context, ops []Operation) error {
+	data, err := json.Marshal(ops)
+	if err != nil {
+		return err
+	}
+	if len(data) > maxConfigMapBytes {
+		return fmt.Errorf("%d pending operations do not fit in a ConfigMap; use a file store on a volume", len(ops))
+	}
+
+	configMaps := s.Clientset.CoreV1().ConfigMaps(s.Namespace)
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm, err := configMaps.Get(ctx, s.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			_, err = configMaps.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: s.Name, Namespace: s.Namespace},
+				Data:       map[string]string{configMapKey: string(data)},
+			}, metav1.CreateOptions{})
+			return err
+		}
+		if err != nil {
+			return err
+		}
+
+		if cm.Data == nil {
+			cm.Data = make(map[string]string)
+		}
+		cm.Data[configMapKey] = string(data)
+
+		_, err = configMaps.Update(ctx, cm, metav1.UpdateOptions{})
+		return err
+	})
+}
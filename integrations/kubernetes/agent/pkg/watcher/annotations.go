@@ -0,0 +1,159 @@
+package watcher
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/saturn/k8s-agent/pkg/monitor"
+	batchv1 "k8s.io/api/batch/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+// Annotations that tune the monitor of a CronJob
+const (
+	AnnotationName           = "saturn.co/name"
+	AnnotationTimezone       = "saturn.co/timezone"
+	AnnotationIntervalSec    = "saturn.co/interval-sec"
+	AnnotationCaptureOutput  = "saturn.co/capture-output"
+	AnnotationCaptureLimitKb = "saturn.co/capture-limit-kb"
+	AnnotationAlertChannels  = "saturn.co/alert-channels"
+)
+
+// maxCaptureLimitKb bounds saturn.co/capture-limit-kb
+const maxCaptureLimitKb = 1024
+
+// ReasonInvalidAnnotation is the event reason reported for CronJobs whose
+// Saturn annotations cannot be applied
+const ReasonInvalidAnnotation = "InvalidAnnotation"
+
+// buildMonitorSpec derives the desired monitor from a CronJob and its
+// annotations. Every invalid annotation is reported in the returned error.
+func (w *CronJobWatcher) buildMonitorSpec(cronJob *batchv1.CronJob) (*monitor.MonitorSpec, error) {
+	annotations := cronJob.Annotations
+	var errs []error
+
+	spec := &monitor.MonitorSpec{
+		GraceSec: w.settings().DefaultGraceSec,
+		Tags:     w.buildTags(cronJob),
+	}
+
+	schedule, scheduleErr := parseSchedule(cronJob.Spec.Schedule, cronJob.Spec.TimeZone)
+	if scheduleErr == nil {
+		spec.ScheduleType = schedule.ScheduleType
+		spec.CronExpr = schedule.CronExpr
+		spec.IntervalSec = schedule.IntervalSec
+		spec.Timezone = schedule.Timezone
+	}
+
+	if name, ok := annotations[AnnotationName]; ok {
+		name = strings.TrimSpace(name)
+		switch {
+		case name == "":
+			errs = append(errs, fmt.Errorf("%s must not be empty", AnnotationName))
+		case len(name) > maxMonitorNameLen:
+			errs = append(errs, fmt.Errorf("%s must be at most %d characters", AnnotationName, maxMonitorNameLen))
+		default:
+			spec.Name = name
+		}
+	} else {
+		name, err := w.monitorName(cronJob)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		spec.Name = name
+	}
+
+	if value, ok := annotations[AnnotationGraceSec]; ok {
+		if strings.TrimSpace(value) == GraceSecAuto {
+			spec.GraceSec = w.autoGraceSec(cronJob)
+		} else if graceSec, err := parsePositiveInt(AnnotationGraceSec, value); err != nil {
+			errs = append(errs, fmt.Errorf("%w or %q", err, GraceSecAuto))
+		} else {
+			spec.GraceSec = graceSec
+		}
+	}
+
+	if tz, ok := annotations[AnnotationTimezone]; ok {
+		tz = strings.TrimSpace(tz)
+		if _, err := time.LoadLocation(tz); err != nil || tz == "" {
+			errs = append(errs, fmt.Errorf("%s: unknown time zone %q", AnnotationTimezone, tz))
+		} else {
+			spec.Timezone = &tz
+		}
+	}
+
+	if value, ok := annotations[AnnotationIntervalSec]; ok {
+		intervalSec, err := parsePositiveInt(AnnotationIntervalSec, value)
+		if err != nil {
+			errs = append(errs, err)
+		} else {
+			spec.ScheduleType = "INTERVAL"
+			spec.IntervalSec = intervalSec
+			spec.CronExpr = ""
+		}
+	} else if scheduleErr != nil {
+		// The interval annotation replaces the schedule, so its errors only
+		// matter without one
+		errs = append(errs, scheduleErr)
+	}
+
+	if value, ok := annotations[AnnotationCaptureOutput]; ok {
+		capture, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s must be \"true\" or \"false\", got %q", AnnotationCaptureOutput, value))
+		} else {
+			spec.CaptureOutput = &capture
+		}
+	}
+
+	if value, ok := annotations[AnnotationCaptureLimitKb]; ok {
+		limit, err := parsePositiveInt(AnnotationCaptureLimitKb, value)
+		switch {
+		case err != nil:
+			errs = append(errs, err)
+		case limit > maxCaptureLimitKb:
+			errs = append(errs, fmt.Errorf("%s must be at most %d, got %d", AnnotationCaptureLimitKb, maxCaptureLimitKb, limit))
+		default:
+			spec.CaptureLimitKb = &limit
+		}
+	}
+
+	if value, ok := annotations[AnnotationAlertChannels]; ok {
+		channels := dedupe(parseList(value))
+		if len(channels) == 0 {
+			errs = append(errs, fmt.Errorf("%s must list at least one alert channel ID", AnnotationAlertChannels))
+		} else {
+			spec.AlertChannelIDs = channels
+		}
+	}
+
+	if policy, ok := annotations[AnnotationDeletionPolicy]; ok {
+		if err := ValidateDeletionPolicy(policy); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", AnnotationDeletionPolicy, err))
+		}
+	}
+
+	return spec, utilerrors.NewAggregate(errs)
+}
+
+func parsePositiveInt(annotation, value string) (int, error) {
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("%s must be a positive integer, got %q", annotation, value)
+	}
+	return n, nil
+}
+
+// parseList splits a comma-separated list, trimming whitespace and dropping
+// empty entries
+func parseList(s string) []string {
+	var result []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
@@ -2,14 +2,31 @@ package watcher
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/saturn/k8s-agent/pkg/monitor"
+	"github.com/saturn/k8s-agent/pkg/outbox"
+	"golang.org/x/time/rate"
 	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	batchlisters "k8s.io/client-go/listers/batch/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 )
 
@@ -22,151 +39,434 @@ const (
 	
 	// Default grace period
 	DefaultGraceSec = 300
+
+	// Source component of events recorded on CronJobs
+	EventComponent = "saturn-agent"
+
+	// Backoff bounds for retrying a failed CronJob sync
+	RetryBaseDelay = time.Second
+	RetryMaxDelay  = 5 * time.Minute
 )
 
-// CronJobWatcher watches CronJobs and syncs them with Saturn
+// CronJobWatcher watches CronJobs and syncs them with Saturn. Events from a
+// shared informer are reduced to namespace/name keys on a rate-limited work
+// queue, so each CronJob is synced by at most one worker at a time and failed
+// syncs are retried with per-key exponential backoff.
 type CronJobWatcher struct {
 	clientset      *kubernetes.Clientset
-	namespace      string
-	syncPeriod     time.Duration
-	monitorManager *monitor.Manager
+	monitorManager monitorClient
+	opts           Options
+
+	informerFactory informers.SharedInformerFactory
+	lister          batchlisters.CronJobLister
+	synced          []cache.InformerSynced
+	queue           workqueue.RateLimitingInterface
+	recorder        record.EventRecorder
+
+	// plan records the changes of a dry run, set when Options.DryRun is true
+	plan *Plan
+
+	// Run reporting, set up when Options.ReportRuns is true
+	podInformerFactory informers.SharedInformerFactory
+	jobLister          batchlisters.JobLister
+	podLister          corelisters.PodLister
+	jobQueue           workqueue.RateLimitingInterface
+
+	// monitors caches ping tokens and capture settings by monitor ID
+	monitorsMu sync.Mutex
+	monitors   map[string]*monitor.Monitor
+
+	// tombstones holds the last known state of deleted CronJobs until their
+	// monitors have been cleaned up, keyed like the work queue.
+	tombstonesMu sync.Mutex
+	tombstones   map[string]*batchv1.CronJob
+
+	// apiFailing is set by failed syncs and triggers an outbox replay on the
+	// next success
+	outboxMu   sync.Mutex
+	apiFailing bool
+	lastReplay time.Time
+
+	// applied remembers the desired state last synced per CronJob key, so
+	// unchanged CronJobs are not PATCHed on every event
+	appliedMu sync.Mutex
+	applied   map[string]appliedSpec
+
+	// current holds the settings in effect, replaced by UpdateSettings
+	settingsMu sync.RWMutex
+	current    *Settings
+}
+
+// Options configures a CronJobWatcher
+type Options struct {
+	// Namespace to watch (empty = all namespaces)
+	Namespace string
+
+	// SyncPeriod is the informer resync period for full reconciliation
+	SyncPeriod time.Duration
+
+	// Workers is the number of CronJobs synced concurrently
+	Workers int
+
+	// Finalizers adds the cleanup finalizer to managed CronJobs so their
+	// monitors are removed even if the agent misses the delete event.
+	// When false, existing finalizers are removed instead.
+	Finalizers bool
+
+	// ClusterName is stamped into monitor names, tags and ownership markers
+	// so the same CronJob in different clusters gets distinct monitors
+	ClusterName string
+
+	// Settings are the initial reloadable settings
+	Settings Settings
+
+	// ReportRuns watches Jobs and Pods of monitored CronJobs and reports
+	// their runs, unless a CronJob opts out with saturn.co/report-runs
+	ReportRuns bool
+
+	// AutoGrace bounds grace periods derived with saturn.co/grace-sec: auto
+	AutoGrace AutoGraceOptions
+
+	// GC configures collection of orphaned monitors
+	GC GCOptions
+
+	// Outbox keeps failed syncs and deletes across restarts (nil disables)
+	Outbox *outbox.Outbox
+
+	// MonitorRefreshInterval is how long a synced monitor is trusted before
+	// an unchanged CronJob is applied again, repairing monitors changed or
+	// deleted in Saturn (0 = only when the CronJob changes)
+	MonitorRefreshInterval time.Duration
+
+	// DryRun reads CronJobs and monitors as usual but records the changes
+	// it would make in a Plan instead of making them
+	DryRun bool
 }
 
 // NewCronJobWatcher creates a new CronJob watcher
 func NewCronJobWatcher(
 	clientset *kubernetes.Clientset,
-	namespace string,
-	syncPeriod time.Duration,
 	monitorManager *monitor.Manager,
+	opts Options,
 ) *CronJobWatcher {
-	return &CronJobWatcher{
-		clientset:      clientset,
-		namespace:      namespace,
-		syncPeriod:     syncPeriod,
-		monitorManager: monitorManager,
+	informerFactory := informers.NewSharedInformerFactoryWithOptions(
+		clientset,
+		opts.SyncPeriod,
+		informers.WithNamespace(opts.Namespace),
+	)
+	cronJobInformer := informerFactory.Batch().V1().CronJobs()
+	jobInformer := informerFactory.Batch().V1().Jobs()
+
+	// Events surface problems such as invalid annotations on the CronJob
+	// itself. A dry run only logs them and never writes to the cluster.
+	var recorder record.EventRecorder
+	var client monitorClient = monitorManager
+	var plan *Plan
+	if opts.DryRun {
+		recorder = dryRunRecorder{}
+		plan = newPlan()
+		client = &dryRunClient{monitorClient: monitorManager, plan: plan}
+	} else {
+		broadcaster := record.NewBroadcaster()
+		broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: clientset.CoreV1().Events("")})
+		recorder = broadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: EventComponent})
+	}
+
+	w := &CronJobWatcher{
+		clientset:       clientset,
+		monitorManager:  client,
+		opts:            opts,
+		informerFactory: informerFactory,
+		lister:          cronJobInformer.Lister(),
+		jobLister:       jobInformer.Lister(),
+		synced:          []cache.InformerSynced{cronJobInformer.Informer().HasSynced, jobInformer.Informer().HasSynced},
+		queue:           workqueue.NewRateLimitingQueueWithConfig(newRateLimiter(), workqueue.RateLimitingQueueConfig{Name: "cronjobs"}),
+		recorder:        recorder,
+		plan:            plan,
+		monitors:        make(map[string]*monitor.Monitor),
+		tombstones:      make(map[string]*batchv1.CronJob),
+		applied:         make(map[string]appliedSpec),
+		current:         &opts.Settings,
 	}
+
+	cronJobInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    w.onAdd,
+		UpdateFunc: w.onUpdate,
+		DeleteFunc: w.onDelete,
+	})
+
+	// Job history feeds automatic grace periods
+	jobInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: w.onJobCompleted,
+	})
+
+	if opts.ReportRuns {
+		w.setupRunReporting()
+	}
+
+	return w
 }
 
-// Run starts the watcher
+// newRateLimiter combines per-item exponential backoff with an overall bucket
+// limit so a burst of failures cannot flood the Saturn API
+func newRateLimiter() workqueue.RateLimiter {
+	return workqueue.NewMaxOfRateLimiter(
+		workqueue.NewItemExponentialFailureRateLimiter(RetryBaseDelay, RetryMaxDelay),
+		&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(10), 100)},
+	)
+}
+
+// Run starts the informers and workers and blocks until ctx is cancelled
 func (w *CronJobWatcher) Run(ctx context.Context) {
-	klog.Info("Starting CronJob watcher")
+	if err := w.Start(ctx); err != nil {
+		klog.Errorf("Failed to start CronJob watcher: %v", err)
+		return
+	}
 
-	// Initial sync
-	if err := w.syncAll(ctx); err != nil {
-		klog.Errorf("Initial sync failed: %v", err)
+	w.RunWorkers(ctx)
+}
+
+// Start starts the informers and waits for the CronJob cache to sync. Standby
+// replicas call Start without RunWorkers so their cache is warm when they are
+// elected; events seen meanwhile stay queued for the next leader.
+func (w *CronJobWatcher) Start(ctx context.Context) error {
+	klog.Info("Starting CronJob informer")
+
+	w.informerFactory.Start(ctx.Done())
+	if w.podInformerFactory != nil {
+		w.podInformerFactory.Start(ctx.Done())
 	}
 
-	// Watch for changes
-	go w.watchCronJobs(ctx)
+	if !cache.WaitForCacheSync(ctx.Done(), w.synced...) {
+		return fmt.Errorf("timed out waiting for CronJob cache to sync")
+	}
 
-	// Periodic full sync
-	ticker := time.NewTicker(w.syncPeriod)
-	defer ticker.Stop()
+	klog.Info("CronJob cache synced")
+	return nil
+}
 
-	for {
-		select {
-		case <-ctx.Done():
-			klog.Info("Stopping CronJob watcher")
-			return
-		case <-ticker.C:
-			if err := w.syncAll(ctx); err != nil {
-				klog.Errorf("Periodic sync failed: %v", err)
-			}
+// RunWorkers replays the outbox, then processes queued CronJobs and runs
+// orphaned monitor GC until ctx is cancelled
+func (w *CronJobWatcher) RunWorkers(ctx context.Context) {
+	defer w.queue.ShutDown()
+
+	if !w.startOutbox(ctx) {
+		return
+	}
+
+	klog.Infof("Starting %d CronJob sync workers", w.opts.Workers)
+
+	for i := 0; i < w.opts.Workers; i++ {
+		go wait.UntilWithContext(ctx, w.runWorker, time.Second)
+	}
+
+	go w.runGC(ctx)
+
+	if w.jobQueue != nil {
+		defer w.jobQueue.ShutDown()
+
+		for i := 0; i < w.opts.Workers; i++ {
+			go wait.UntilWithContext(ctx, w.runJobWorker, time.Second)
 		}
 	}
+
+	<-ctx.Done()
+	klog.Info("Stopping CronJob watcher")
 }
 
-// syncAll performs a full sync of all CronJobs
-func (w *CronJobWatcher) syncAll(ctx context.Context) error {
-	klog.Info("Starting full CronJob sync")
+func (w *CronJobWatcher) onAdd(obj interface{}) {
+	cronJob, ok := obj.(*batchv1.CronJob)
+	if !ok {
+		return
+	}
 
-	cronJobs, err := w.clientset.BatchV1().CronJobs(w.namespace).List(ctx, v1.ListOptions{})
+	key, err := cache.MetaNamespaceKeyFunc(cronJob)
 	if err != nil {
-		return fmt.Errorf("failed to list CronJobs: %w", err)
+		klog.Errorf("Failed to get key for CronJob: %v", err)
+		return
 	}
 
-	synced := 0
-	for _, cronJob := range cronJobs.Items {
-		if w.shouldSync(&cronJob) {
-			if err := w.syncCronJob(ctx, &cronJob); err != nil {
-				klog.Errorf("Failed to sync CronJob %s/%s: %v", cronJob.Namespace, cronJob.Name, err)
-			} else {
-				synced++
-			}
-		}
+	if w.shouldSync(cronJob) || hasFinalizer(cronJob) {
+		w.queue.Add(key)
 	}
+}
 
-	klog.Infof("Full sync completed: %d/%d CronJobs synced", synced, len(cronJobs.Items))
-	return nil
+func (w *CronJobWatcher) onUpdate(oldObj, newObj interface{}) {
+	cronJob, ok := newObj.(*batchv1.CronJob)
+	if !ok {
+		return
+	}
+
+	klog.V(4).Infof("CronJob updated: %s/%s", cronJob.Namespace, cronJob.Name)
+
+	if w.shouldSync(cronJob) || hasFinalizer(cronJob) {
+		w.enqueue(cronJob)
+	}
 }
 
-// watchCronJobs watches for CronJob events
-func (w *CronJobWatcher) watchCronJobs(ctx context.Context) {
-	for {
-		select {
-		case <-ctx.Done():
+func (w *CronJobWatcher) onDelete(obj interface{}) {
+	cronJob, ok := obj.(*batchv1.CronJob)
+	if !ok {
+		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			klog.Warning("Received non-CronJob object in delete event")
 			return
-		default:
-			if err := w.watchOnce(ctx); err != nil {
-				klog.Errorf("Watch error: %v", err)
-				time.Sleep(5 * time.Second)
-			}
 		}
+		cronJob, ok = tombstone.Obj.(*batchv1.CronJob)
+		if !ok {
+			klog.Warning("Received non-CronJob tombstone in delete event")
+			return
+		}
+	}
+
+	if !w.shouldSync(cronJob) {
+		return
+	}
+
+	key, err := cache.MetaNamespaceKeyFunc(cronJob)
+	if err != nil {
+		klog.Errorf("Failed to get key for CronJob: %v", err)
+		return
+	}
+
+	w.tombstonesMu.Lock()
+	w.tombstones[key] = cronJob
+	w.tombstonesMu.Unlock()
+	w.forgetApplied(key)
+
+	w.queue.Add(key)
+}
+
+func (w *CronJobWatcher) enqueue(cronJob *batchv1.CronJob) {
+	key, err := cache.MetaNamespaceKeyFunc(cronJob)
+	if err != nil {
+		klog.Errorf("Failed to get key for CronJob %s/%s: %v", cronJob.Namespace, cronJob.Name, err)
+		return
+	}
+	w.queue.Add(key)
+}
+
+func (w *CronJobWatcher) runWorker(ctx context.Context) {
+	for w.processNextItem(ctx) {
+	}
+}
+
+// processNextItem syncs one key from the queue, requeueing it with backoff on failure
+func (w *CronJobWatcher) processNextItem(ctx context.Context) bool {
+	item, quit := w.queue.Get()
+	if quit {
+		return false
+	}
+	defer w.queue.Done(item)
+
+	key := item.(string)
+
+	if w.plan != nil {
+		w.plan.beginSync(key)
+		ctx = withPlanKey(ctx, key)
+	}
+
+	if err := w.syncKey(ctx, key); err != nil {
+		klog.Errorf("Failed to sync CronJob %s (attempt %d): %v", key, w.queue.NumRequeues(key)+1, err)
+		w.queueOperation(ctx, key, err)
+		w.queue.AddRateLimited(key)
+		return true
 	}
+
+	w.queue.Forget(key)
+	w.completeOperation(ctx, key)
+	return true
 }
 
-// watchOnce performs a single watch operation
-func (w *CronJobWatcher) watchOnce(ctx context.Context) error {
-	watcher, err := w.clientset.BatchV1().CronJobs(w.namespace).Watch(ctx, v1.ListOptions{})
+// syncKey reconciles the CronJob stored under key, or cleans up its monitor if it was deleted
+func (w *CronJobWatcher) syncKey(ctx context.Context, key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
 	if err != nil {
-		return fmt.Errorf("failed to create watcher: %w", err)
+		return err
 	}
-	defer watcher.Stop()
 
-	klog.Info("Watching for CronJob events")
+	cronJob, err := w.lister.CronJobs(namespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		w.tombstonesMu.Lock()
+		deleted := w.tombstones[key]
+		w.tombstonesMu.Unlock()
 
-	for {
-		select {
-		case <-ctx.Done():
+		if deleted == nil {
 			return nil
-		case event, ok := <-watcher.ResultChan():
-			if !ok {
-				return fmt.Errorf("watch channel closed")
-			}
+		}
 
-			w.handleEvent(ctx, event)
+		if err := w.deleteCronJobMonitor(ctx, deleted); err != nil {
+			return fmt.Errorf("failed to delete monitor: %w", err)
 		}
+
+		w.clearTombstone(key)
+		return nil
+	}
+	if err != nil {
+		return err
 	}
-}
 
-// handleEvent handles a watch event
-func (w *CronJobWatcher) handleEvent(ctx context.Context, event watch.Event) {
-	cronJob, ok := event.Object.(*batchv1.CronJob)
-	if !ok {
-		klog.Warning("Received non-CronJob object in watch event")
-		return
+	if err := w.cleanUpPredecessor(ctx, key, cronJob); err != nil {
+		return err
 	}
 
-	klog.V(4).Infof("CronJob event: %s %s/%s", event.Type, cronJob.Namespace, cronJob.Name)
+	if cronJob.DeletionTimestamp != nil {
+		return w.finalizeCronJob(ctx, cronJob)
+	}
 
-	switch event.Type {
-	case watch.Added, watch.Modified:
-		if w.shouldSync(cronJob) {
-			if err := w.syncCronJob(ctx, cronJob); err != nil {
-				klog.Errorf("Failed to sync CronJob %s/%s: %v", cronJob.Namespace, cronJob.Name, err)
-			}
+	if !w.shouldSync(cronJob) || !w.opts.Finalizers {
+		// Opted out, or finalizers disabled: never leave ours behind
+		if err := w.removeFinalizer(ctx, cronJob); err != nil {
+			return fmt.Errorf("failed to remove finalizer: %w", err)
+		}
+		if !w.shouldSync(cronJob) {
+			// GC may disable the monitor meanwhile; sync in full if re-enabled
+			w.forgetApplied(key)
+			w.clearSpecHash(ctx, cronJob)
+			return nil
 		}
-	case watch.Deleted:
-		if w.shouldSync(cronJob) {
-			if err := w.deleteCronJobMonitor(ctx, cronJob); err != nil {
-				klog.Errorf("Failed to delete monitor for CronJob %s/%s: %v", cronJob.Namespace, cronJob.Name, err)
-			}
+	} else if err := w.ensureFinalizer(ctx, cronJob); err != nil {
+		return fmt.Errorf("failed to add finalizer: %w", err)
+	}
+
+	return w.syncCronJob(ctx, cronJob)
+}
+
+// cleanUpPredecessor applies the deletion policy to the monitor of a CronJob
+// that was deleted and recreated under the same name before its cleanup ran.
+// Recreating a CronJob does not cancel the cleanup of the old monitor.
+func (w *CronJobWatcher) cleanUpPredecessor(ctx context.Context, key string, cronJob *batchv1.CronJob) error {
+	deleted := w.pendingDelete(key)
+	if deleted == nil {
+		return nil
+	}
+
+	sameObject := deleted.UID != "" && deleted.UID == cronJob.UID
+	sameMonitor := deleted.Annotations[AnnotationMonitorID] == cronJob.Annotations[AnnotationMonitorID]
+	if !sameObject && !sameMonitor {
+		klog.Infof("CronJob %s was recreated, cleaning up the monitor of the deleted one first", key)
+		if err := w.deleteCronJobMonitor(ctx, deleted); err != nil {
+			return fmt.Errorf("failed to delete monitor of deleted CronJob: %w", err)
 		}
 	}
+
+	w.clearTombstone(key)
+	return nil
+}
+
+func (w *CronJobWatcher) clearTombstone(key string) {
+	w.tombstonesMu.Lock()
+	delete(w.tombstones, key)
+	w.tombstonesMu.Unlock()
 }
 
 // shouldSync checks if a CronJob should be synced with Saturn
 func (w *CronJobWatcher) shouldSync(cronJob *batchv1.CronJob) bool {
+	// CronJobs outside the configured namespaces and selector are left alone
+	if !w.settings().inScope(cronJob) {
+		return false
+	}
+
 	// Check if Saturn integration is explicitly enabled
 	if enabled, ok := cronJob.Annotations[AnnotationEnabled]; ok && enabled == "true" {
 		return true
@@ -183,90 +483,132 @@ func (w *CronJobWatcher) shouldSync(cronJob *batchv1.CronJob) bool {
 
 // syncCronJob syncs a CronJob with Saturn
 func (w *CronJobWatcher) syncCronJob(ctx context.Context, cronJob *batchv1.CronJob) error {
-	monitorName := fmt.Sprintf("%s/%s", cronJob.Namespace, cronJob.Name)
-	
-	// Get grace period from annotation or use default
-	graceSec := DefaultGraceSec
-	if graceStr, ok := cronJob.Annotations[AnnotationGraceSec]; ok {
-		// Parse grace seconds from annotation
-		var g int
-		if _, err := fmt.Sscanf(graceStr, "%d", &g); err == nil && g > 0 {
-			graceSec = g
+	monitorSpec, err := w.buildMonitorSpec(cronJob)
+	if err != nil {
+		// Retrying cannot fix the annotations; wait for the CronJob to change
+		klog.Errorf("Not syncing CronJob %s/%s: %v", cronJob.Namespace, cronJob.Name, err)
+		reason := ReasonInvalidAnnotation
+		if errors.Is(err, errInvalidSchedule) {
+			reason = ReasonInvalidSchedule
 		}
-	}
-
-	// Get tags from annotation
-	tags := []string{
-		"kubernetes",
-		fmt.Sprintf("namespace:%s", cronJob.Namespace),
-	}
-	if tagsStr, ok := cronJob.Annotations[AnnotationTags]; ok {
-		// Simple comma-separated tags
-		// In production, use proper parsing
-		tags = append(tags, tagsStr)
+		w.recorder.Event(cronJob, corev1.EventTypeWarning, reason, err.Error())
+		return nil
 	}
 
 	// Check if monitor already exists
 	monitorID := cronJob.Annotations[AnnotationMonitorID]
-	
-	monitorSpec := &monitor.MonitorSpec{
-		Name:         monitorName,
-		ScheduleType: "CRON",
-		CronExpr:     cronJob.Spec.Schedule,
-		Timezone:     cronJob.Spec.TimeZone,
-		GraceSec:     graceSec,
-		Tags:         tags,
+
+	// Status updates and resyncs rarely change the desired state; only call
+	// Saturn when it differs from the last successful sync
+	key := cronJob.Namespace + "/" + cronJob.Name
+	if monitorID != "" && w.upToDate(key, cronJob, specHash(monitorID, monitorSpec, isSuspended(cronJob))) {
+		klog.V(4).Infof("Monitor %s of CronJob %s/%s is up to date", monitorID, cronJob.Namespace, cronJob.Name)
+		return nil
 	}
 
+	var current *monitor.Monitor
 	if monitorID != "" {
 		// Update existing monitor
 		klog.V(2).Infof("Updating monitor %s for CronJob %s/%s", monitorID, cronJob.Namespace, cronJob.Name)
-		if err := w.monitorManager.UpdateMonitor(ctx, monitorID, monitorSpec); err != nil {
+		current, err = w.monitorManager.UpdateMonitor(ctx, monitorID, monitorSpec)
+		if errors.Is(err, monitor.ErrNotFound) {
+			// Deleted in Saturn; re-attach by external key or create a new one
+			klog.Warningf("Monitor %s of CronJob %s/%s no longer exists", monitorID, cronJob.Namespace, cronJob.Name)
+			monitorID = ""
+		} else if err != nil {
 			return fmt.Errorf("failed to update monitor: %w", err)
 		}
-	} else {
-		// Create new monitor
-		klog.Infof("Creating monitor for CronJob %s/%s", cronJob.Namespace, cronJob.Name)
-		newMonitorID, err := w.monitorManager.CreateMonitor(ctx, monitorSpec)
+	}
+
+	if monitorID == "" {
+		current, err = w.adoptOrCreateMonitor(ctx, cronJob, monitorSpec)
 		if err != nil {
-			return fmt.Errorf("failed to create monitor: %w", err)
+			return err
 		}
 
-		// Update CronJob with monitor ID annotation
-		if err := w.addMonitorAnnotation(ctx, cronJob, newMonitorID); err != nil {
-			klog.Warningf("Failed to add monitor ID annotation: %v", err)
-			// Don't return error, monitor is created successfully
+		// Update CronJob with monitor ID annotation. On failure the sync is
+		// retried and the monitor adopted by its external key.
+		if err := w.addMonitorAnnotation(ctx, cronJob, current.ID); err != nil {
+			return fmt.Errorf("failed to add monitor ID annotation: %w", err)
 		}
 	}
 
+	w.storeMonitor(current)
+
+	if err := w.syncSuspend(ctx, cronJob, current, monitorSpec); err != nil {
+		return err
+	}
+
+	w.rememberApplied(ctx, key, cronJob, specHash(current.ID, monitorSpec, isSuspended(cronJob)))
 	return nil
 }
 
-// deleteCronJobMonitor deletes the monitor for a CronJob
-func (w *CronJobWatcher) deleteCronJobMonitor(ctx context.Context, cronJob *batchv1.CronJob) error {
-	monitorID := cronJob.Annotations[AnnotationMonitorID]
-	if monitorID == "" {
-		return nil // No monitor to delete
+// adoptOrCreateMonitor returns the monitor tagged with the CronJob's external
+// key, updated to spec, or creates one if none exists
+func (w *CronJobWatcher) adoptOrCreateMonitor(ctx context.Context, cronJob *batchv1.CronJob, spec *monitor.MonitorSpec) (*monitor.Monitor, error) {
+	tag := keyTag(w.opts.ClusterName, cronJob)
+	listed, err := w.monitorManager.ListMonitors(ctx, tag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up monitor by external key: %w", err)
+	}
+
+	// Never rely on the server honouring the tag filter: adopting an
+	// unrelated monitor would overwrite it
+	var existing []monitor.Monitor
+	for _, m := range listed {
+		if hasTag(m.Tags, tag) {
+			existing = append(existing, m)
+		}
+	}
+
+	if len(existing) > 0 {
+		adopted := existing[0]
+		if len(existing) > 1 {
+			klog.Warningf("Found %d monitors for CronJob %s/%s, adopting %s", len(existing), cronJob.Namespace, cronJob.Name, adopted.ID)
+		}
+
+		klog.Infof("Adopting monitor %s for CronJob %s/%s", adopted.ID, cronJob.Namespace, cronJob.Name)
+		updated, err := w.monitorManager.UpdateMonitor(ctx, adopted.ID, spec)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update adopted monitor: %w", err)
+		}
+		return updated, nil
 	}
 
-	klog.Infof("Deleting monitor %s for CronJob %s/%s", monitorID, cronJob.Namespace, cronJob.Name)
-	return w.monitorManager.DeleteMonitor(ctx, monitorID)
+	klog.Infof("Creating monitor for CronJob %s/%s", cronJob.Namespace, cronJob.Name)
+	spec.IdempotencyKey = idempotencyKey(w.opts.ClusterName, cronJob)
+
+	created, err := w.monitorManager.CreateMonitor(ctx, spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create monitor: %w", err)
+	}
+	return created, nil
 }
 
-// addMonitorAnnotation adds the monitor ID annotation to a CronJob
+// addMonitorAnnotation adds the monitor ID annotation to a CronJob. A merge
+// patch only touches the annotation, and transient failures are retried.
 func (w *CronJobWatcher) addMonitorAnnotation(ctx context.Context, cronJob *batchv1.CronJob, monitorID string) error {
-	// Get fresh copy
-	fresh, err := w.clientset.BatchV1().CronJobs(cronJob.Namespace).Get(ctx, cronJob.Name, v1.GetOptions{})
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{AnnotationMonitorID: monitorID},
+		},
+	})
 	if err != nil {
 		return err
 	}
 
-	if fresh.Annotations == nil {
-		fresh.Annotations = make(map[string]string)
+	if w.plan != nil {
+		w.plan.record(ctx, Action{Action: ActionAnnotate, MonitorID: monitorID, Details: AnnotationMonitorID})
+		return nil
 	}
-	fresh.Annotations[AnnotationMonitorID] = monitorID
 
-	_, err = w.clientset.BatchV1().CronJobs(cronJob.Namespace).Update(ctx, fresh, v1.UpdateOptions{})
-	return err
+	return retry.OnError(retry.DefaultBackoff, isRetriable, func() error {
+		_, err := w.clientset.BatchV1().CronJobs(cronJob.Namespace).Patch(ctx, cronJob.Name, types.MergePatchType, patch, v1.PatchOptions{})
+		return err
+	})
 }
 
+// isRetriable reports whether a failed API write is worth retrying
+func isRetriable(err error) bool {
+	return apierrors.IsConflict(err) || apierrors.IsServerTimeout(err) || apierrors.IsTooManyRequests(err)
+}
@@ -0,0 +1,105 @@
+package watcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/saturn/k8s-agent/pkg/monitor"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/klog/v2"
+)
+
+// AnnotationDeletionPolicy overrides the agent's deletion policy for one CronJob
+const AnnotationDeletionPolicy = "saturn.co/deletion-policy"
+
+// Deletion policies: what happens to a monitor when its CronJob is deleted
+const (
+	// DeletionPolicyDelete deletes the monitor and its run history
+	DeletionPolicyDelete = "delete"
+
+	// DeletionPolicyDisable disables the monitor. A CronJob recreated with the
+	// same cluster, namespace and name re-attaches to it and keeps its history.
+	DeletionPolicyDisable = "disable"
+
+	// DeletionPolicyRetain leaves the monitor active and marks it retained, so
+	// garbage collection does not collect it
+	DeletionPolicyRetain = "retain"
+)
+
+// ValidateDeletionPolicy checks a deletion policy name
+func ValidateDeletionPolicy(policy string) error {
+	switch policy {
+	case DeletionPolicyDelete, DeletionPolicyDisable, DeletionPolicyRetain:
+		return nil
+	}
+	return fmt.Errorf("deletion policy must be %q, %q or %q, got %q",
+		DeletionPolicyDelete, DeletionPolicyDisable, DeletionPolicyRetain, policy)
+}
+
+// deletionPolicy returns the policy for cronJob: its annotation if valid,
+// otherwise the agent default
+func (w *CronJobWatcher) deletionPolicy(cronJob *batchv1.CronJob) string {
+	defaultPolicy := w.settings().DeletionPolicy
+
+	policy, ok := cronJob.Annotations[AnnotationDeletionPolicy]
+	if !ok {
+		return defaultPolicy
+	}
+
+	if err := ValidateDeletionPolicy(policy); err != nil {
+		klog.Warningf("CronJob %s/%s: %s: %v, using %q", cronJob.Namespace, cronJob.Name, AnnotationDeletionPolicy, err, defaultPolicy)
+		return defaultPolicy
+	}
+	return policy
+}
+
+// deleteCronJobMonitor applies the deletion policy to the monitor of a deleted CronJob
+func (w *CronJobWatcher) deleteCronJobMonitor(ctx context.Context, cronJob *batchv1.CronJob) error {
+	monitorID := cronJob.Annotations[AnnotationMonitorID]
+	if monitorID == "" {
+		return nil // No monitor to delete
+	}
+
+	switch w.deletionPolicy(cronJob) {
+	case DeletionPolicyRetain:
+		klog.Infof("Retaining monitor %s for deleted CronJob %s/%s", monitorID, cronJob.Namespace, cronJob.Name)
+		return w.retainMonitor(ctx, monitorID)
+
+	case DeletionPolicyDisable:
+		klog.Infof("Disabling monitor %s for deleted CronJob %s/%s", monitorID, cronJob.Namespace, cronJob.Name)
+		err := w.monitorManager.DisableMonitor(ctx, monitorID)
+		if errors.Is(err, monitor.ErrNotFound) {
+			return nil
+		}
+		return err
+
+	default:
+		klog.Infof("Deleting monitor %s for CronJob %s/%s", monitorID, cronJob.Namespace, cronJob.Name)
+		return w.monitorManager.DeleteMonitor(ctx, monitorID)
+	}
+}
+
+// retainMonitor replaces the owner marker of a retained monitor with the
+// retained marker. A recreated CronJob still adopts it by external key, which
+// restores the owner marker.
+func (w *CronJobWatcher) retainMonitor(ctx context.Context, monitorID string) error {
+	m, err := w.monitorManager.GetMonitor(ctx, monitorID)
+	if errors.Is(err, monitor.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	tags, ok := retainedTags(m.Tags)
+	if !ok {
+		return nil // Already retained, or not created by the agent
+	}
+
+	err = w.monitorManager.SetMonitorTags(ctx, monitorID, tags)
+	if errors.Is(err, monitor.ErrNotFound) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,205 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/saturn/k8s-agent/pkg/monitor"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+)
+
+// monitorClient is the part of the Saturn API the watcher uses
+type monitorClient interface {
+	CreateMonitor(ctx context.Context, spec *monitor.MonitorSpec) (*monitor.Monitor, error)
+	UpdateMonitor(ctx context.Context, id string, spec *monitor.MonitorSpec) (*monitor.Monitor, error)
+	DeleteMonitor(ctx context.Context, id string) error
+	DisableMonitor(ctx context.Context, id string) error
+	EnableMonitor(ctx context.Context, id string, nextDueAt time.Time) error
+	SetMonitorTags(ctx context.Context, id string, tags []string) error
+	ListMonitors(ctx context.Context, tag string) ([]monitor.Monitor, error)
+	GetMonitor(ctx context.Context, id string) (*monitor.Monitor, error)
+	SendPing(ctx context.Context, token string, ping *monitor.Ping) error
+}
+
+// dryRunClient reads monitors from Saturn but records writes in the plan
+// instead of sending them
+type dryRunClient struct {
+	monitorClient
+	plan *Plan
+}
+
+func (c *dryRunClient) CreateMonitor(ctx context.Context, spec *monitor.MonitorSpec) (*monitor.Monitor, error) {
+	c.plan.record(ctx, Action{Action: ActionCreate, Details: describeSpec(spec)})
+	return &monitor.Monitor{ID: PlannedMonitorID, Name: spec.Name, Status: monitor.StatusOK}, nil
+}
+
+// UpdateMonitor records the fields that would change and returns the
+// monitor as it is
+func (c *dryRunClient) UpdateMonitor(ctx context.Context, id string, spec *monitor.MonitorSpec) (*monitor.Monitor, error) {
+	current, err := c.monitorClient.GetMonitor(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if changes := diffMonitor(current, spec); len(changes) > 0 {
+		c.plan.record(ctx, Action{Action: ActionUpdate, MonitorID: id, Details: strings.Join(changes, ", ")})
+	}
+	return current, nil
+}
+
+func (c *dryRunClient) DeleteMonitor(ctx context.Context, id string) error {
+	c.plan.record(ctx, Action{Action: ActionDelete, MonitorID: id})
+	return nil
+}
+
+func (c *dryRunClient) DisableMonitor(ctx context.Context, id string) error {
+	c.plan.record(ctx, Action{Action: ActionDisable, MonitorID: id})
+	return nil
+}
+
+func (c *dryRunClient) EnableMonitor(ctx context.Context, id string, nextDueAt time.Time) error {
+	details := ""
+	if !nextDueAt.IsZero() {
+		details = "next run " + nextDueAt.UTC().Format(time.RFC3339)
+	}
+	c.plan.record(ctx, Action{Action: ActionEnable, MonitorID: id, Details: details})
+	return nil
+}
+
+func (c *dryRunClient) SetMonitorTags(ctx context.Context, id string, tags []string) error {
+	current, err := c.monitorClient.GetMonitor(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if added, removed := diffSets(current.Tags, tags); len(added)+len(removed) > 0 {
+		c.plan.record(ctx, Action{Action: ActionUpdate, MonitorID: id, Details: "tags" + formatSetDiff(added, removed)})
+	}
+	return nil
+}
+
+func (c *dryRunClient) SendPing(ctx context.Context, token string, ping *monitor.Ping) error {
+	klog.V(2).Infof("[dry-run] Not sending %s ping", ping.State)
+	return nil
+}
+
+// dryRunRecorder logs the Events a dry run would record instead of creating
+// them. Messages may refer to monitors that were only planned.
+type dryRunRecorder struct{}
+
+func (dryRunRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	name := "unknown object"
+	if accessor, err := meta.Accessor(object); err == nil {
+		name = accessor.GetNamespace() + "/" + accessor.GetName()
+	}
+	klog.V(2).Infof("[dry-run] CronJob %s: would record %s event %s: %s", name, eventtype, reason, message)
+}
+
+func (r dryRunRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.Event(object, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func (r dryRunRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.Eventf(object, eventtype, reason, messageFmt, args...)
+}
+
+// describeSpec summarises the schedule of a monitor to be created
+func describeSpec(spec *monitor.MonitorSpec) string {
+	schedule := spec.CronExpr
+	if spec.ScheduleType == "INTERVAL" {
+		schedule = fmt.Sprintf("every %ds", spec.IntervalSec)
+	}
+	return fmt.Sprintf("%s (%s, grace %ds)", spec.Name, schedule, spec.GraceSec)
+}
+
+// diffMonitor lists the fields of current that an update to spec would
+// change. Fields spec leaves unset are not compared.
+func diffMonitor(current *monitor.Monitor, spec *monitor.MonitorSpec) []string {
+	var changes []string
+	change := func(field string, from, to interface{}) {
+		changes = append(changes, fmt.Sprintf("%s %v -> %v", field, from, to))
+	}
+
+	if current.Name != spec.Name {
+		change("name", fmt.Sprintf("%q", current.Name), fmt.Sprintf("%q", spec.Name))
+	}
+	if current.ScheduleType != spec.ScheduleType {
+		change("scheduleType", current.ScheduleType, spec.ScheduleType)
+	}
+	if current.CronExpr != spec.CronExpr {
+		change("cronExpr", fmt.Sprintf("%q", current.CronExpr), fmt.Sprintf("%q", spec.CronExpr))
+	}
+	if current.IntervalSec != spec.IntervalSec {
+		change("intervalSec", current.IntervalSec, spec.IntervalSec)
+	}
+	if spec.Timezone != nil && current.Timezone != *spec.Timezone {
+		change("timezone", fmt.Sprintf("%q", current.Timezone), fmt.Sprintf("%q", *spec.Timezone))
+	}
+	if current.GraceSec != spec.GraceSec {
+		change("graceSec", current.GraceSec, spec.GraceSec)
+	}
+	if added, removed := diffSets(current.Tags, spec.Tags); len(added)+len(removed) > 0 {
+		changes = append(changes, "tags"+formatSetDiff(added, removed))
+	}
+	if spec.CaptureOutput != nil && (current.CaptureOutput == nil || *current.CaptureOutput != *spec.CaptureOutput) {
+		var from interface{} = "unset"
+		if current.CaptureOutput != nil {
+			from = *current.CaptureOutput
+		}
+		change("captureOutput", from, *spec.CaptureOutput)
+	}
+	if spec.CaptureLimitKb != nil && (current.CaptureLimitKb == nil || *current.CaptureLimitKb != *spec.CaptureLimitKb) {
+		var from interface{} = "unset"
+		if current.CaptureLimitKb != nil {
+			from = *current.CaptureLimitKb
+		}
+		change("captureLimitKb", from, *spec.CaptureLimitKb)
+	}
+	if spec.AlertChannelIDs != nil {
+		if added, removed := diffSets(current.AlertChannelIDs, spec.AlertChannelIDs); len(added)+len(removed) > 0 {
+			changes = append(changes, "alertChannelIds"+formatSetDiff(added, removed))
+		}
+	}
+
+	return changes
+}
+
+// diffSets returns the sorted elements only in to and only in from
+func diffSets(from, to []string) (added, removed []string) {
+	inFrom := make(map[string]bool, len(from))
+	for _, s := range from {
+		inFrom[s] = true
+	}
+	inTo := make(map[string]bool, len(to))
+	for _, s := range to {
+		inTo[s] = true
+		if !inFrom[s] {
+			added = append(added, s)
+		}
+	}
+	for _, s := range from {
+		if !inTo[s] {
+			removed = append(removed, s)
+		}
+	}
+
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
+
+func formatSetDiff(added, removed []string) string {
+	var b strings.Builder
+	for _, s := range added {
+		b.WriteString(" +" + s)
+	}
+	for _, s := range removed {
+		b.WriteString(" -" + s)
+	}
+	return b.String()
+}
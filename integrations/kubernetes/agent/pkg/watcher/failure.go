@@ -0,0 +1,208 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/saturn/k8s-agent/pkg/monitor"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/klog/v2"
+)
+
+// Failure reasons reported by the agent in addition to the Kubernetes
+// reasons it passes through (OOMKilled, DeadlineExceeded, BackoffLimitExceeded, ...)
+const (
+	ReasonEvicted          = "Evicted"
+	ReasonFailedScheduling = "FailedScheduling"
+	ReasonError            = "Error"
+)
+
+// stuckReasons are container waiting reasons that keep a pod from ever
+// starting without outside intervention
+var stuckReasons = map[string]bool{
+	"ImagePullBackOff":           true,
+	"ErrImagePull":               true,
+	"InvalidImageName":           true,
+	"CreateContainerConfigError": true,
+	"CreateContainerError":       true,
+}
+
+// stuckRunTimeout is how long a Job's pod may be stuck before the run is
+// reported as failed
+const stuckRunTimeout = 5 * time.Minute
+
+// classifyFailure explains why job failed. Pod causes such as OOM kills,
+// evictions and image pull errors are preferred over the Job's own condition,
+// which usually only says the backoff limit or deadline was hit. Warning
+// events are the fallback once pods are gone.
+func (w *CronJobWatcher) classifyFailure(ctx context.Context, job *batchv1.Job) *monitor.Failure {
+	if failure := podFailure(w.jobPods(job)); failure != nil {
+		return failure
+	}
+
+	for _, cond := range job.Status.Conditions {
+		if cond.Type == batchv1.JobFailed && cond.Status == corev1.ConditionTrue && cond.Reason != "" {
+			return &monitor.Failure{Reason: cond.Reason, Message: cond.Message}
+		}
+	}
+
+	return w.eventFailure(ctx, job)
+}
+
+// stuckFailure returns the failure of a pod of job that has been unable to
+// start for longer than stuckRunTimeout, or nil
+func (w *CronJobWatcher) stuckFailure(job *batchv1.Job) *monitor.Failure {
+	for _, pod := range w.jobPods(job) {
+		if pod.Status.Phase != corev1.PodPending || time.Since(pod.CreationTimestamp.Time) < stuckRunTimeout {
+			continue
+		}
+		if failure := waitingFailure(pod); failure != nil {
+			return failure
+		}
+		if failure := schedulingFailure(pod); failure != nil {
+			return failure
+		}
+	}
+	return nil
+}
+
+// podFailure returns the most specific failure among pods, latest pod first
+func podFailure(pods []*corev1.Pod) *monitor.Failure {
+	sort.Slice(pods, func(i, j int) bool {
+		return pods[j].CreationTimestamp.Before(&pods[i].CreationTimestamp)
+	})
+
+	for _, pod := range pods {
+		if pod.Status.Reason == ReasonEvicted {
+			return &monitor.Failure{Reason: ReasonEvicted, Message: pod.Status.Message, Node: pod.Spec.NodeName}
+		}
+		if failure := terminatedFailure(pod); failure != nil {
+			return failure
+		}
+		if failure := waitingFailure(pod); failure != nil {
+			return failure
+		}
+		if failure := schedulingFailure(pod); failure != nil {
+			return failure
+		}
+	}
+	return nil
+}
+
+// terminatedFailure describes the latest container of pod that exited with an error
+func terminatedFailure(pod *corev1.Pod) *monitor.Failure {
+	var failed *corev1.ContainerStateTerminated
+	var container string
+
+	for _, status := range allContainerStatuses(pod) {
+		terminated := status.State.Terminated
+		if terminated == nil {
+			terminated = status.LastTerminationState.Terminated
+		}
+		if terminated == nil || (terminated.ExitCode == 0 && terminated.Reason != "OOMKilled") {
+			continue
+		}
+		if failed == nil || terminated.FinishedAt.After(failed.FinishedAt.Time) {
+			failed = terminated
+			container = status.Name
+		}
+	}
+
+	if failed == nil {
+		return nil
+	}
+
+	reason := failed.Reason
+	if reason == "" {
+		reason = ReasonError
+	}
+
+	message := failed.Message
+	switch {
+	case reason == "OOMKilled":
+		message = fmt.Sprintf("OOMKilled in container %s (exit code %d)", container, failed.ExitCode)
+	case message == "":
+		message = fmt.Sprintf("Container %s exited with code %d", container, failed.ExitCode)
+	}
+
+	return &monitor.Failure{Reason: reason, Message: message, Container: container, Node: pod.Spec.NodeName}
+}
+
+// waitingFailure describes a container of pod that cannot start
+func waitingFailure(pod *corev1.Pod) *monitor.Failure {
+	for _, status := range allContainerStatuses(pod) {
+		waiting := status.State.Waiting
+		if waiting == nil || !stuckReasons[waiting.Reason] {
+			continue
+		}
+
+		message := waiting.Message
+		if message == "" {
+			message = fmt.Sprintf("Container %s is waiting: %s", status.Name, waiting.Reason)
+		}
+		return &monitor.Failure{Reason: waiting.Reason, Message: message, Container: status.Name, Node: pod.Spec.NodeName}
+	}
+	return nil
+}
+
+// schedulingFailure describes a pod the scheduler could not place
+func schedulingFailure(pod *corev1.Pod) *monitor.Failure {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodScheduled && cond.Status == corev1.ConditionFalse && cond.Reason == corev1.PodReasonUnschedulable {
+			return &monitor.Failure{Reason: ReasonFailedScheduling, Message: cond.Message}
+		}
+	}
+	return nil
+}
+
+func allContainerStatuses(pod *corev1.Pod) []corev1.ContainerStatus {
+	statuses := make([]corev1.ContainerStatus, 0, len(pod.Status.InitContainerStatuses)+len(pod.Status.ContainerStatuses))
+	statuses = append(statuses, pod.Status.InitContainerStatuses...)
+	return append(statuses, pod.Status.ContainerStatuses...)
+}
+
+// eventFailure returns the latest Warning event recorded for job or its pods
+func (w *CronJobWatcher) eventFailure(ctx context.Context, job *batchv1.Job) *monitor.Failure {
+	uids := []string{string(job.UID)}
+	for _, pod := range w.jobPods(job) {
+		uids = append(uids, string(pod.UID))
+	}
+
+	var latest *corev1.Event
+	for _, uid := range uids {
+		selector := fields.AndSelectors(
+			fields.OneTermEqualSelector("involvedObject.uid", uid),
+			fields.OneTermEqualSelector("type", corev1.EventTypeWarning),
+		)
+
+		events, err := w.clientset.CoreV1().Events(job.Namespace).List(ctx, v1.ListOptions{FieldSelector: selector.String()})
+		if err != nil {
+			klog.V(2).Infof("Failed to list events of Job %s/%s: %v", job.Namespace, job.Name, err)
+			return nil
+		}
+
+		for i := range events.Items {
+			event := &events.Items[i]
+			if latest == nil || eventTime(event).After(eventTime(latest)) {
+				latest = event
+			}
+		}
+	}
+
+	if latest == nil {
+		return nil
+	}
+	return &monitor.Failure{Reason: latest.Reason, Message: latest.Message}
+}
+
+func eventTime(event *corev1.Event) time.Time {
+	if !event.LastTimestamp.IsZero() {
+		return event.LastTimestamp.Time
+	}
+	return event.EventTime.Time
+}
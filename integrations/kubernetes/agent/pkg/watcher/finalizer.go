@@ -0,0 +1,142 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+)
+
+// FinalizerCleanup holds managed CronJobs in Terminating until the agent has
+// cleaned up their Saturn monitor
+const FinalizerCleanup = "saturn.co/cleanup"
+
+func hasFinalizer(cronJob *batchv1.CronJob) bool {
+	for _, f := range cronJob.Finalizers {
+		if f == FinalizerCleanup {
+			return true
+		}
+	}
+	return false
+}
+
+// finalizeCronJob cleans up the monitor of a CronJob that is being deleted and
+// then releases the finalizer so the deletion can complete
+func (w *CronJobWatcher) finalizeCronJob(ctx context.Context, cronJob *batchv1.CronJob) error {
+	if !hasFinalizer(cronJob) {
+		return nil
+	}
+
+	if err := w.deleteCronJobMonitor(ctx, cronJob); err != nil {
+		return fmt.Errorf("failed to delete monitor: %w", err)
+	}
+
+	if err := w.removeFinalizer(ctx, cronJob); err != nil {
+		return fmt.Errorf("failed to remove finalizer: %w", err)
+	}
+
+	klog.Infof("Released finalizer on CronJob %s/%s", cronJob.Namespace, cronJob.Name)
+	return nil
+}
+
+// ensureFinalizer adds the cleanup finalizer to a managed CronJob
+func (w *CronJobWatcher) ensureFinalizer(ctx context.Context, cronJob *batchv1.CronJob) error {
+	if hasFinalizer(cronJob) {
+		return nil
+	}
+
+	if w.plan != nil {
+		w.plan.record(ctx, Action{Action: ActionAddFinalizer, Details: FinalizerCleanup})
+		return nil
+	}
+
+	return updateFinalizers(ctx, w.clientset, cronJob.Namespace, cronJob.Name, func(finalizers []string) []string {
+		return append(finalizers, FinalizerCleanup)
+	})
+}
+
+// removeFinalizer drops the cleanup finalizer from a CronJob
+func (w *CronJobWatcher) removeFinalizer(ctx context.Context, cronJob *batchv1.CronJob) error {
+	if !hasFinalizer(cronJob) {
+		return nil
+	}
+
+	if w.plan != nil {
+		w.plan.record(ctx, Action{Action: ActionRemoveFinalizer, Details: FinalizerCleanup})
+		return nil
+	}
+
+	return updateFinalizers(ctx, w.clientset, cronJob.Namespace, cronJob.Name, withoutCleanupFinalizer)
+}
+
+func withoutCleanupFinalizer(finalizers []string) []string {
+	var result []string
+	for _, f := range finalizers {
+		if f != FinalizerCleanup {
+			result = append(result, f)
+		}
+	}
+	return result
+}
+
+// updateFinalizers applies mutate to a fresh copy of the CronJob's finalizers,
+// retrying on conflicts so finalizers owned by other controllers are preserved
+func updateFinalizers(ctx context.Context, clientset kubernetes.Interface, namespace, name string, mutate func([]string) []string) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		fresh, err := clientset.BatchV1().CronJobs(namespace).Get(ctx, name, v1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		fresh.Finalizers = mutate(fresh.Finalizers)
+
+		_, err = clientset.BatchV1().CronJobs(namespace).Update(ctx, fresh, v1.UpdateOptions{})
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	})
+}
+
+// RemoveAllFinalizers strips the cleanup finalizer from every CronJob in
+// namespace (empty = all namespaces). It runs before the agent is uninstalled
+// so CronJobs deleted afterwards do not hang in Terminating.
+func RemoveAllFinalizers(ctx context.Context, clientset kubernetes.Interface, namespace string) error {
+	removed := 0
+	opts := v1.ListOptions{Limit: 500}
+
+	for {
+		cronJobs, err := clientset.BatchV1().CronJobs(namespace).List(ctx, opts)
+		if err != nil {
+			return fmt.Errorf("failed to list CronJobs: %w", err)
+		}
+
+		for i := range cronJobs.Items {
+			cronJob := &cronJobs.Items[i]
+			if !hasFinalizer(cronJob) {
+				continue
+			}
+
+			if err := updateFinalizers(ctx, clientset, cronJob.Namespace, cronJob.Name, withoutCleanupFinalizer); err != nil {
+				return fmt.Errorf("failed to remove finalizer from CronJob %s/%s: %w", cronJob.Namespace, cronJob.Name, err)
+			}
+			removed++
+		}
+
+		if cronJobs.Continue == "" {
+			break
+		}
+		opts.Continue = cronJobs.Continue
+	}
+
+	klog.Infof("Removed %s finalizer from %d CronJobs", FinalizerCleanup, removed)
+	return nil
+}
@@ -0,0 +1,169 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/saturn/k8s-agent/pkg/monitor"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+)
+
+// GC actions for orphaned monitors
+const (
+	GCActionDelete  = "delete"
+	GCActionDisable = "disable"
+)
+
+// GCOptions configures garbage collection of orphaned monitors
+type GCOptions struct {
+	// Interval between GC passes (0 disables GC)
+	Interval time.Duration
+
+	// GracePeriod a monitor must stay orphaned before it is collected
+	GracePeriod time.Duration
+
+	// Action taken on orphans: GCActionDelete or GCActionDisable
+	Action string
+
+	// ReportOnly logs orphans without changing them
+	ReportOnly bool
+}
+
+// runGC periodically collects monitors owned by this cluster whose CronJob no
+// longer exists. It runs alongside the workers, so only the leader collects.
+func (w *CronJobWatcher) runGC(ctx context.Context) {
+	if w.opts.GC.Interval <= 0 {
+		return
+	}
+
+	klog.Infof("Starting orphaned monitor GC every %s (grace period %s, action %s, report-only %t)",
+		w.opts.GC.Interval, w.opts.GC.GracePeriod, w.opts.GC.Action, w.opts.GC.ReportOnly)
+
+	// First time each monitor was seen orphaned, for the grace period
+	orphanedSince := make(map[string]time.Time)
+
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := w.collectGarbage(ctx, orphanedSince); err != nil {
+			klog.Errorf("Orphaned monitor GC failed: %v", err)
+		}
+	}, w.opts.GC.Interval)
+}
+
+// collectGarbage runs one GC pass
+func (w *CronJobWatcher) collectGarbage(ctx context.Context, orphanedSince map[string]time.Time) error {
+	monitors, err := w.monitorManager.ListMonitors(ctx, clusterTag(w.opts.ClusterName))
+	if err != nil {
+		return fmt.Errorf("failed to list monitors: %w", err)
+	}
+
+	now := time.Now()
+	orphaned := make(map[string]bool)
+	collected := 0
+	var planned []Action
+
+	for _, m := range monitors {
+		if isRetained(m.Tags) {
+			// Kept on purpose by the retain deletion policy
+			continue
+		}
+
+		o, ok := ownerFromTags(m.Tags)
+		if !ok || o.Cluster != w.opts.ClusterName {
+			continue
+		}
+		if w.opts.Namespace != "" && o.Namespace != w.opts.Namespace {
+			continue
+		}
+		if m.Status == monitor.StatusDisabled {
+			// Already inactive, possibly kept by the disable deletion policy
+			// for a CronJob to re-attach to
+			continue
+		}
+
+		reason := w.orphanReason(o, m.ID)
+		if reason == "" {
+			continue
+		}
+
+		orphaned[m.ID] = true
+		since, ok := orphanedSince[m.ID]
+		if !ok {
+			since = now
+			orphanedSince[m.ID] = now
+		}
+
+		if now.Sub(since) < w.opts.GC.GracePeriod {
+			klog.V(2).Infof("Monitor %s (%s) orphaned: %s; collecting after grace period", m.ID, o, reason)
+			continue
+		}
+
+		if w.opts.GC.ReportOnly {
+			klog.Infof("Orphaned monitor %s (%s): %s; would %s (report-only)", m.ID, o, reason, w.opts.GC.Action)
+			continue
+		}
+
+		if w.plan != nil {
+			planned = append(planned, Action{CronJob: o.String(), Action: w.opts.GC.Action, MonitorID: m.ID, Details: "orphaned: " + reason})
+			continue
+		}
+
+		klog.Infof("Orphaned monitor %s (%s): %s; running %s", m.ID, o, reason, w.opts.GC.Action)
+		if w.opts.GC.Action == GCActionDisable {
+			err = w.monitorManager.DisableMonitor(ctx, m.ID)
+		} else {
+			err = w.monitorManager.DeleteMonitor(ctx, m.ID)
+		}
+		if err != nil {
+			klog.Errorf("Failed to %s orphaned monitor %s: %v", w.opts.GC.Action, m.ID, err)
+			continue
+		}
+
+		delete(orphanedSince, m.ID)
+		collected++
+	}
+
+	// Forget monitors that were adopted again or removed elsewhere
+	for id := range orphanedSince {
+		if !orphaned[id] {
+			delete(orphanedSince, id)
+		}
+	}
+
+	if w.plan != nil {
+		w.plan.setGC(planned)
+	}
+
+	klog.V(2).Infof("Orphaned monitor GC: %d monitors checked, %d orphaned, %d collected", len(monitors), len(orphaned), collected)
+	return nil
+}
+
+// orphanReason explains why the monitor with monitorID is orphaned, or
+// returns "" if its CronJob still claims it
+func (w *CronJobWatcher) orphanReason(o owner, monitorID string) string {
+	cronJob, err := w.lister.CronJobs(o.Namespace).Get(o.Name)
+	if apierrors.IsNotFound(err) {
+		return "CronJob no longer exists"
+	}
+	if err != nil || cronJob.DeletionTimestamp != nil {
+		// Deletion in progress is handled by the finalizer
+		return ""
+	}
+
+	linkedID := cronJob.Annotations[AnnotationMonitorID]
+	switch {
+	case linkedID == monitorID:
+		return ""
+	case string(cronJob.UID) != o.UID:
+		return "CronJob was recreated"
+	case !w.shouldSync(cronJob):
+		return "monitoring disabled on CronJob"
+	case linkedID != "":
+		return fmt.Sprintf("CronJob is linked to monitor %s", linkedID)
+	default:
+		// Created but not annotated yet
+		return ""
+	}
+}
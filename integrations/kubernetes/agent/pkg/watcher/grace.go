@@ -0,0 +1,167 @@
+package watcher
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+)
+
+// GraceSecAuto as the value of saturn.co/grace-sec derives the grace period
+// from the CronJob's deadlines and the durations of its past Jobs
+const GraceSecAuto = "auto"
+
+const (
+	// autoGraceMinSamples is the number of successful Jobs needed before
+	// their durations are trusted
+	autoGraceMinSamples = 3
+
+	// autoGraceHistory bounds how many of the latest successful Jobs make up
+	// the duration baseline
+	autoGraceHistory = 20
+
+	// autoGraceMarginPercent is added to the p95 duration, but at least
+	// autoGraceMinMargin
+	autoGraceMarginPercent = 25
+	autoGraceMinMargin     = 30 * time.Second
+)
+
+// AutoGraceOptions bounds grace periods derived with saturn.co/grace-sec: auto
+type AutoGraceOptions struct {
+	// Min and Max clamp the derived grace period
+	Min time.Duration
+	Max time.Duration
+}
+
+// autoGraceSec derives a grace period covering how late a run may start and
+// how long it usually takes
+func (w *CronJobWatcher) autoGraceSec(cronJob *batchv1.CronJob) int {
+	var grace time.Duration
+	var parts []string
+
+	if deadline := cronJob.Spec.StartingDeadlineSeconds; deadline != nil {
+		grace += time.Duration(*deadline) * time.Second
+		parts = append(parts, fmt.Sprintf("startingDeadlineSeconds %d", *deadline))
+	}
+
+	duration, samples := w.durationBaseline(cronJob)
+	if samples > 0 {
+		parts = append(parts, fmt.Sprintf("p95 of %d runs plus margin %s", samples, duration))
+	}
+
+	if deadline := cronJob.Spec.JobTemplate.Spec.ActiveDeadlineSeconds; deadline != nil {
+		// A run cannot take longer than its deadline
+		active := time.Duration(*deadline) * time.Second
+		if samples == 0 || duration > active {
+			duration = active
+			parts = append(parts, fmt.Sprintf("activeDeadlineSeconds %d", *deadline))
+		}
+	}
+
+	if samples == 0 && cronJob.Spec.JobTemplate.Spec.ActiveDeadlineSeconds == nil {
+		duration = time.Duration(w.settings().DefaultGraceSec) * time.Second
+		parts = append(parts, "no history yet, default")
+	}
+
+	grace += duration
+
+	// Whole minutes keep small drifts from updating the monitor on every run
+	if rounded := grace.Truncate(time.Minute); rounded < grace {
+		grace = rounded + time.Minute
+	}
+
+	if lower := w.opts.AutoGrace.Min; grace < lower {
+		grace = lower
+	}
+	if upper := w.opts.AutoGrace.Max; upper > 0 && grace > upper {
+		grace = upper
+	}
+
+	klog.V(2).Infof("Auto grace period of CronJob %s/%s is %s (%s)", cronJob.Namespace, cronJob.Name, grace, strings.Join(parts, ", "))
+	return int(grace / time.Second)
+}
+
+// durationBaseline returns the p95 duration of the CronJob's latest
+// successful Jobs plus a margin, and the number of Jobs it is based on. It
+// returns 0 samples until there are enough Jobs.
+func (w *CronJobWatcher) durationBaseline(cronJob *batchv1.CronJob) (time.Duration, int) {
+	jobs, err := w.jobLister.Jobs(cronJob.Namespace).List(labels.Everything())
+	if err != nil {
+		return 0, 0
+	}
+
+	type run struct {
+		finished time.Time
+		duration time.Duration
+	}
+
+	var runs []run
+	for _, job := range jobs {
+		if ref := v1.GetControllerOf(job); ref == nil || ref.UID != cronJob.UID {
+			continue
+		}
+
+		finished, succeeded, at := jobFinished(job)
+		if !finished || !succeeded || job.Status.StartTime == nil {
+			continue
+		}
+		runs = append(runs, run{finished: at, duration: at.Sub(job.Status.StartTime.Time)})
+	}
+
+	if len(runs) < autoGraceMinSamples {
+		return 0, 0
+	}
+
+	sort.Slice(runs, func(i, j int) bool { return runs[i].finished.After(runs[j].finished) })
+	if len(runs) > autoGraceHistory {
+		runs = runs[:autoGraceHistory]
+	}
+
+	durations := make([]time.Duration, len(runs))
+	for i, r := range runs {
+		durations[i] = r.duration
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+
+	// Nearest-rank percentile
+	p95 := durations[(len(durations)*95+99)/100-1]
+
+	margin := p95 * autoGraceMarginPercent / 100
+	if margin < autoGraceMinMargin {
+		margin = autoGraceMinMargin
+	}
+
+	return p95 + margin, len(durations)
+}
+
+// onJobCompleted resyncs CronJobs with an automatic grace period when one of
+// their Jobs succeeds, so the monitor follows the duration baseline
+func (w *CronJobWatcher) onJobCompleted(oldObj, newObj interface{}) {
+	oldJob, ok := oldObj.(*batchv1.Job)
+	if !ok {
+		return
+	}
+	job, ok := newObj.(*batchv1.Job)
+	if !ok {
+		return
+	}
+
+	if _, succeeded, _ := jobFinished(oldJob); succeeded {
+		return
+	}
+	if _, succeeded, _ := jobFinished(job); !succeeded {
+		return
+	}
+
+	cronJob := w.owningCronJob(job)
+	if cronJob == nil || strings.TrimSpace(cronJob.Annotations[AnnotationGraceSec]) != GraceSecAuto {
+		return
+	}
+
+	w.enqueue(cronJob)
+}
@@ -0,0 +1,155 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/saturn/k8s-agent/pkg/monitor"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// defaultCaptureLimitKb matches Saturn's default for monitors that do
+	// not report a limit
+	defaultCaptureLimitKb = 32
+
+	// logHeadShare is the part of the budget kept from the start of a log;
+	// the rest goes to the end, where stack traces usually are
+	logHeadShare = 4
+
+	// logFetchTimeout bounds fetching the logs of one container
+	logFetchTimeout = 30 * time.Second
+)
+
+// captureBudget returns the output size in bytes allowed for a monitor
+func captureBudget(m *monitor.Monitor) int {
+	limitKb := defaultCaptureLimitKb
+	if m.CaptureLimitKb != nil && *m.CaptureLimitKb > 0 {
+		limitKb = *m.CaptureLimitKb
+	}
+	return limitKb * 1024
+}
+
+// failedPodLogs returns the logs of the failed containers of job's newest
+// failed pod, including the previous instance of restarted containers,
+// truncated to budget bytes. Failures are logged and yield partial output.
+func (w *CronJobWatcher) failedPodLogs(ctx context.Context, job *batchv1.Job, budget int) string {
+	pods := w.jobPods(job)
+	sort.Slice(pods, func(i, j int) bool {
+		return pods[j].CreationTimestamp.Before(&pods[i].CreationTimestamp)
+	})
+
+	for _, pod := range pods {
+		type source struct {
+			container string
+			previous  bool
+		}
+
+		var sources []source
+		for _, status := range allContainerStatuses(pod) {
+			current := status.State.Terminated
+			last := status.LastTerminationState.Terminated
+
+			if last != nil && last.ExitCode != 0 {
+				sources = append(sources, source{status.Name, true})
+			}
+			if current != nil && (current.ExitCode != 0 || current.Reason == "OOMKilled") {
+				sources = append(sources, source{status.Name, false})
+			}
+		}
+
+		if len(sources) == 0 {
+			continue
+		}
+
+		var out strings.Builder
+		for _, src := range sources {
+			header := fmt.Sprintf("=== %s/%s ===\n", pod.Name, src.container)
+			if src.previous {
+				header = fmt.Sprintf("=== %s/%s (previous) ===\n", pod.Name, src.container)
+			}
+
+			logs, err := w.containerLogs(ctx, pod, src.container, src.previous, budget/len(sources))
+			if err != nil {
+				klog.V(2).Infof("Failed to get logs of %s/%s container %s: %v", pod.Namespace, pod.Name, src.container, err)
+				continue
+			}
+
+			out.WriteString(header)
+			out.WriteString(logs)
+			if !strings.HasSuffix(logs, "\n") {
+				out.WriteString("\n")
+			}
+		}
+		return out.String()
+	}
+
+	return ""
+}
+
+// containerLogs streams the logs of one container, keeping at most budget bytes
+func (w *CronJobWatcher) containerLogs(ctx context.Context, pod *corev1.Pod, container string, previous bool, budget int) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, logFetchTimeout)
+	defer cancel()
+
+	stream, err := w.clientset.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+		Container: container,
+		Previous:  previous,
+	}).Stream(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer stream.Close()
+
+	return truncateHeadTail(stream, budget)
+}
+
+// truncateHeadTail reads r and, if it is longer than budget, keeps its start
+// and end with a marker in between, using memory proportional to budget
+func truncateHeadTail(r io.Reader, budget int) (string, error) {
+	headBudget := budget / logHeadShare
+	tailBudget := budget - headBudget
+
+	var head, tail []byte
+	total := 0
+	buf := make([]byte, 32*1024)
+
+	for {
+		n, err := r.Read(buf)
+		chunk := buf[:n]
+		total += n
+
+		if room := headBudget - len(head); room > 0 {
+			if room > len(chunk) {
+				room = len(chunk)
+			}
+			head = append(head, chunk[:room]...)
+			chunk = chunk[room:]
+		}
+
+		tail = append(tail, chunk...)
+		if len(tail) > 2*tailBudget {
+			tail = append(tail[:0], tail[len(tail)-tailBudget:]...)
+		}
+
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if total <= budget {
+		return string(head) + string(tail), nil
+	}
+
+	omitted := total - len(head) - tailBudget
+	return fmt.Sprintf("%s\n[... %d bytes truncated ...]\n%s", head, omitted, tail[len(tail)-tailBudget:]), nil
+}
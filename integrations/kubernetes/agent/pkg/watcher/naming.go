@@ -0,0 +1,66 @@
+package watcher
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	batchv1 "k8s.io/api/batch/v1"
+)
+
+// DefaultNameTemplate names monitors <cluster>/<namespace>/<name>
+const DefaultNameTemplate = "{{.Cluster}}/{{.Namespace}}/{{.Name}}"
+
+// maxMonitorNameLen is the longest monitor name the Saturn API accepts
+const maxMonitorNameLen = 100
+
+var defaultNameTemplate = template.Must(ParseNameTemplate(DefaultNameTemplate))
+
+// nameData is the data available to monitor name templates
+type nameData struct {
+	Cluster     string
+	Namespace   string
+	Name        string
+	Labels      map[string]string
+	Annotations map[string]string
+}
+
+// ParseNameTemplate parses a monitor name template. Missing map keys render
+// as empty strings so templates can use optional labels.
+func ParseNameTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("name").Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid name template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// monitorName renders the name of the monitor for cronJob
+func (w *CronJobWatcher) monitorName(cronJob *batchv1.CronJob) (string, error) {
+	tmpl := w.settings().NameTemplate
+	if tmpl == nil {
+		tmpl = defaultNameTemplate
+	}
+
+	var buf bytes.Buffer
+	err := tmpl.Execute(&buf, nameData{
+		Cluster:     w.opts.ClusterName,
+		Namespace:   cronJob.Namespace,
+		Name:        cronJob.Name,
+		Labels:      cronJob.Labels,
+		Annotations: cronJob.Annotations,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to render monitor name: %w", err)
+	}
+
+	name := strings.TrimSpace(buf.String())
+	if name == "" {
+		return "", fmt.Errorf("monitor name template rendered an empty name")
+	}
+	if len(name) > maxMonitorNameLen {
+		return "", fmt.Errorf("monitor name %q is longer than %d characters", name, maxMonitorNameLen)
+	}
+	return name, nil
+}
@@ -0,0 +1,162 @@
+package watcher
+
+import (
+	"context"
+	"time"
+
+	"github.com/saturn/k8s-agent/pkg/outbox"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// outboxLoadRetryPeriod is the wait between attempts to load the outbox
+	outboxLoadRetryPeriod = 5 * time.Second
+
+	// outboxReplayInterval bounds how often a recovered API triggers a replay
+	outboxReplayInterval = 30 * time.Second
+
+	// outboxReportInterval is how often a non-empty outbox is logged
+	outboxReportInterval = time.Minute
+
+	// maxOutboxErrorLen keeps API error bodies from filling the outbox
+	maxOutboxErrorLen = 200
+)
+
+// startOutbox loads the operations left by a previous leader and replays
+// them. It returns false if ctx ended first.
+func (w *CronJobWatcher) startOutbox(ctx context.Context) bool {
+	if w.opts.Outbox == nil {
+		return true
+	}
+
+	// Writing before a successful load would overwrite the stored operations
+	err := wait.PollUntilContextCancel(ctx, outboxLoadRetryPeriod, true, func(ctx context.Context) (bool, error) {
+		if err := w.opts.Outbox.Load(ctx); err != nil {
+			klog.Errorf("%v (retrying)", err)
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		return false
+	}
+
+	w.replayOutbox()
+	go wait.UntilWithContext(ctx, w.reportOutbox, outboxReportInterval)
+	return true
+}
+
+// queueOperation records the failed sync of key in the outbox so it survives
+// agent restarts
+func (w *CronJobWatcher) queueOperation(ctx context.Context, key string, syncErr error) {
+	if w.opts.Outbox == nil {
+		return
+	}
+
+	w.outboxMu.Lock()
+	w.apiFailing = true
+	w.outboxMu.Unlock()
+
+	op := outbox.Operation{Key: key, Kind: outbox.KindSync, Error: syncErr.Error()}
+	if len(op.Error) > maxOutboxErrorLen {
+		op.Error = op.Error[:maxOutboxErrorLen]
+	}
+
+	if deleted := w.pendingDelete(key); deleted != nil {
+		op.Kind = outbox.KindDelete
+		op.UID = string(deleted.UID)
+		op.MonitorID = deleted.Annotations[AnnotationMonitorID]
+		op.DeletionPolicy = w.deletionPolicy(deleted)
+	}
+
+	if err := w.opts.Outbox.Put(ctx, op); err != nil {
+		klog.Errorf("Failed to queue %s of CronJob %s: %v", op.Kind, key, err)
+	}
+}
+
+// completeOperation clears the outbox entry of key after a successful sync.
+// The first success after failures means the API is back, so the outbox is
+// replayed instead of waiting for each entry's backoff.
+func (w *CronJobWatcher) completeOperation(ctx context.Context, key string) {
+	if w.opts.Outbox == nil {
+		return
+	}
+
+	if err := w.opts.Outbox.Done(ctx, key); err != nil {
+		klog.Errorf("Failed to clear queued operation of CronJob %s: %v", key, err)
+	}
+
+	w.outboxMu.Lock()
+	recovered := w.apiFailing && time.Since(w.lastReplay) >= outboxReplayInterval
+	if recovered {
+		w.apiFailing = false
+	}
+	w.outboxMu.Unlock()
+
+	if recovered {
+		w.replayOutbox()
+	}
+}
+
+// replayOutbox queues the CronJobs of all pending operations in the order
+// they were recorded, restoring tombstones of deleted CronJobs. A tombstone is
+// restored even if a CronJob with the same name exists again, so the monitor
+// of the deleted one is still cleaned up.
+func (w *CronJobWatcher) replayOutbox() {
+	w.outboxMu.Lock()
+	w.lastReplay = time.Now()
+	w.outboxMu.Unlock()
+
+	pending := w.opts.Outbox.Pending()
+	if len(pending) == 0 {
+		return
+	}
+
+	klog.Infof("Replaying %d queued operations", len(pending))
+
+	for _, op := range pending {
+		if op.Kind == outbox.KindDelete && w.pendingDelete(op.Key) == nil {
+			namespace, name, err := cache.SplitMetaNamespaceKey(op.Key)
+			if err != nil {
+				continue
+			}
+
+			w.tombstonesMu.Lock()
+			w.tombstones[op.Key] = &batchv1.CronJob{
+				ObjectMeta: v1.ObjectMeta{
+					Namespace: namespace,
+					Name:      name,
+					UID:       types.UID(op.UID),
+					Annotations: map[string]string{
+						AnnotationMonitorID:      op.MonitorID,
+						AnnotationDeletionPolicy: op.DeletionPolicy,
+					},
+				},
+			}
+			w.tombstonesMu.Unlock()
+		}
+
+		w.queue.Add(op.Key)
+	}
+}
+
+// pendingDelete returns the tombstone of a deleted CronJob stored under key
+// whose monitor has not been cleaned up yet
+func (w *CronJobWatcher) pendingDelete(key string) *batchv1.CronJob {
+	w.tombstonesMu.Lock()
+	defer w.tombstonesMu.Unlock()
+	return w.tombstones[key]
+}
+
+// reportOutbox logs the outbox depth while operations are pending
+func (w *CronJobWatcher) reportOutbox(ctx context.Context) {
+	depth, oldest := w.opts.Outbox.Depth()
+	if depth > 0 {
+		klog.Warningf("%d Saturn operations queued, oldest for %s", depth, oldest.Round(time.Second))
+	}
+}
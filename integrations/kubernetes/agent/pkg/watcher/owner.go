@@ -0,0 +1,121 @@
+package watcher
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+)
+
+const (
+	// TagClusterPrefix marks every monitor the agent owns in a cluster, so a
+	// single tag query finds them all
+	TagClusterPrefix = "k8s-cluster:"
+
+	// TagOwnerPrefix records the CronJob a monitor belongs to as
+	// <cluster>/<namespace>/<name>/<uid>
+	TagOwnerPrefix = "k8s-owner:"
+
+	// TagKeyPrefix holds the monitor's external key, <cluster>/<namespace>/<name>.
+	// Unlike the owner marker it survives the CronJob being recreated, so the
+	// agent can find the monitor without the monitor-id annotation.
+	TagKeyPrefix = "k8s-key:"
+
+	// TagRetainedPrefix replaces the owner marker of a monitor kept by the
+	// retain deletion policy, so garbage collection leaves it alone
+	TagRetainedPrefix = "k8s-retained:"
+)
+
+// owner identifies the CronJob a monitor was created for
+type owner struct {
+	Cluster   string
+	Namespace string
+	Name      string
+	UID       string
+}
+
+func (o owner) String() string {
+	return o.Namespace + "/" + o.Name
+}
+
+func clusterTag(clusterName string) string {
+	return TagClusterPrefix + clusterName
+}
+
+// externalKey identifies the monitor of cronJob independently of its ID
+func externalKey(clusterName string, cronJob *batchv1.CronJob) string {
+	return strings.Join([]string{clusterName, cronJob.Namespace, cronJob.Name}, "/")
+}
+
+func keyTag(clusterName string, cronJob *batchv1.CronJob) string {
+	return TagKeyPrefix + externalKey(clusterName, cronJob)
+}
+
+// idempotencyKey is sent with creates so a retried request for the same
+// CronJob object cannot create a second monitor
+func idempotencyKey(clusterName string, cronJob *batchv1.CronJob) string {
+	sum := sha256.Sum256([]byte(externalKey(clusterName, cronJob) + "/" + string(cronJob.UID)))
+	return hex.EncodeToString(sum[:16])
+}
+
+// ownerTags returns the marker tags stamped on the monitor of cronJob
+func ownerTags(clusterName string, cronJob *batchv1.CronJob) []string {
+	return []string{
+		clusterTag(clusterName),
+		keyTag(clusterName, cronJob),
+		TagOwnerPrefix + strings.Join([]string{clusterName, cronJob.Namespace, cronJob.Name, string(cronJob.UID)}, "/"),
+	}
+}
+
+// ownerFromTags finds and parses the owner marker among a monitor's tags
+func ownerFromTags(tags []string) (owner, bool) {
+	for _, tag := range tags {
+		if !strings.HasPrefix(tag, TagOwnerPrefix) {
+			continue
+		}
+
+		parts := strings.Split(strings.TrimPrefix(tag, TagOwnerPrefix), "/")
+		if len(parts) != 4 {
+			continue
+		}
+
+		return owner{Cluster: parts[0], Namespace: parts[1], Name: parts[2], UID: parts[3]}, true
+	}
+	return owner{}, false
+}
+
+// retainedTags swaps the owner marker among tags for the retained marker. It
+// returns false if there is no owner marker to swap.
+func retainedTags(tags []string) ([]string, bool) {
+	result := make([]string, 0, len(tags))
+	swapped := false
+	for _, tag := range tags {
+		if strings.HasPrefix(tag, TagOwnerPrefix) {
+			tag = TagRetainedPrefix + strings.TrimPrefix(tag, TagOwnerPrefix)
+			swapped = true
+		}
+		result = append(result, tag)
+	}
+	return result, swapped
+}
+
+// isRetained reports whether tags carry the retained marker
+func isRetained(tags []string) bool {
+	for _, tag := range tags {
+		if strings.HasPrefix(tag, TagRetainedPrefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasTag reports whether tags contain tag exactly
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,275 @@
+package watcher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"k8s.io/apimachinery/pkg/labels"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+// Planned actions, recorded instead of performed in dry-run mode
+const (
+	ActionCreate          = "create"
+	ActionUpdate          = "update"
+	ActionDelete          = "delete"
+	ActionDisable         = "disable"
+	ActionEnable          = "enable"
+	ActionAnnotate        = "annotate"
+	ActionAddFinalizer    = "add-finalizer"
+	ActionRemoveFinalizer = "remove-finalizer"
+)
+
+// PlannedMonitorID stands in for the ID of a monitor that would be created
+const PlannedMonitorID = "(new)"
+
+// Action is a change the agent would make to Saturn or a CronJob
+type Action struct {
+	CronJob   string `json:"cronJob"`
+	Action    string `json:"action"`
+	MonitorID string `json:"monitorId,omitempty"`
+	Details   string `json:"details,omitempty"`
+}
+
+func (a Action) String() string {
+	s := a.Action
+	if a.MonitorID != "" {
+		s += " monitor " + a.MonitorID
+	}
+	if a.Details != "" {
+		s += ": " + a.Details
+	}
+	return s
+}
+
+// Plan collects the actions of a dry run. Each sync of a CronJob replaces its
+// actions and each GC pass replaces the orphan actions, so the plan always
+// reflects the latest view of the cluster.
+type Plan struct {
+	mu       sync.Mutex
+	sync     map[string][]Action
+	previous map[string][]Action
+	gc       []Action
+}
+
+func newPlan() *Plan {
+	return &Plan{
+		sync:     make(map[string][]Action),
+		previous: make(map[string][]Action),
+	}
+}
+
+type planKeyContextKey struct{}
+
+// withPlanKey attributes the actions recorded under ctx to the CronJob key
+func withPlanKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, planKeyContextKey{}, key)
+}
+
+// beginSync drops the actions planned by the previous sync of key
+func (p *Plan) beginSync(key string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if actions, ok := p.sync[key]; ok {
+		p.previous[key] = actions
+		delete(p.sync, key)
+	} else {
+		delete(p.previous, key)
+	}
+}
+
+// record adds an action for the CronJob being synced under ctx. Actions are
+// logged when they first appear rather than on every resync.
+func (p *Plan) record(ctx context.Context, a Action) {
+	if key, ok := ctx.Value(planKeyContextKey{}).(string); ok {
+		a.CronJob = key
+	}
+
+	p.mu.Lock()
+	p.sync[a.CronJob] = append(p.sync[a.CronJob], a)
+	previous := p.previous[a.CronJob]
+	p.mu.Unlock()
+
+	logAction(previous, a)
+}
+
+// setGC replaces the actions of the previous GC pass
+func (p *Plan) setGC(actions []Action) {
+	p.mu.Lock()
+	previous := p.gc
+	p.gc = actions
+	p.mu.Unlock()
+
+	for _, a := range actions {
+		logAction(previous, a)
+	}
+}
+
+func logAction(previous []Action, a Action) {
+	for _, seen := range previous {
+		if seen == a {
+			klog.V(2).Infof("[dry-run] CronJob %s: would %s", a.CronJob, a)
+			return
+		}
+	}
+	klog.Infof("[dry-run] CronJob %s: would %s", a.CronJob, a)
+}
+
+// Actions returns the planned actions ordered by CronJob
+func (p *Plan) Actions() []Action {
+	p.mu.Lock()
+	actions := append([]Action(nil), p.gc...)
+	for _, synced := range p.sync {
+		actions = append(actions, synced...)
+	}
+	p.mu.Unlock()
+
+	sort.SliceStable(actions, func(i, j int) bool {
+		return actions[i].CronJob < actions[j].CronJob
+	})
+	return actions
+}
+
+// ServeHTTP writes the planned actions as JSON
+func (p *Plan) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(p.Actions()); err != nil {
+		klog.Errorf("Failed to write plan: %v", err)
+	}
+}
+
+// WriteMetrics writes the number of planned actions by type in the
+// Prometheus text format
+func (p *Plan) WriteMetrics(w io.Writer) {
+	counts := map[string]int{
+		ActionCreate:          0,
+		ActionUpdate:          0,
+		ActionDelete:          0,
+		ActionDisable:         0,
+		ActionEnable:          0,
+		ActionAnnotate:        0,
+		ActionAddFinalizer:    0,
+		ActionRemoveFinalizer: 0,
+	}
+	for _, a := range p.Actions() {
+		counts[a.Action]++
+	}
+
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(w, "# HELP saturn_agent_dry_run_actions Changes the agent would make if it were not in dry-run mode.")
+	fmt.Fprintln(w, "# TYPE saturn_agent_dry_run_actions gauge")
+	for _, name := range names {
+		fmt.Fprintf(w, "saturn_agent_dry_run_actions{action=%q} %d\n", name, counts[name])
+	}
+}
+
+// WriteTable prints the planned actions as a table followed by a summary
+func (p *Plan) WriteTable(w io.Writer) error {
+	actions := p.Actions()
+	if len(actions) == 0 {
+		_, err := fmt.Fprintln(w, "No changes.")
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "CRONJOB\tACTION\tMONITOR\tDETAILS")
+
+	counts := make(map[string]int)
+	for _, a := range actions {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", a.CronJob, a.Action, orDash(a.MonitorID), orDash(a.Details))
+		counts[a.Action]++
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	summary := make([]string, 0, len(names))
+	for _, name := range names {
+		summary = append(summary, fmt.Sprintf("%d %s", counts[name], name))
+	}
+
+	_, err := fmt.Fprintf(w, "\nPlan: %s.\n", strings.Join(summary, ", "))
+	return err
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// Plan returns the changes recorded by a dry run, or nil if the watcher was
+// not created with Options.DryRun
+func (w *CronJobWatcher) Plan() *Plan {
+	return w.plan
+}
+
+// PlanOnce starts the informers, then syncs every CronJob and runs one GC
+// pass without making changes. It backs the plan subcommand, so the watcher
+// must have been created with Options.DryRun.
+func (w *CronJobWatcher) PlanOnce(ctx context.Context) (*Plan, error) {
+	if w.plan == nil {
+		return nil, fmt.Errorf("planning requires a dry-run watcher")
+	}
+
+	if err := w.Start(ctx); err != nil {
+		return nil, err
+	}
+
+	cronJobs, err := w.lister.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list CronJobs: %w", err)
+	}
+
+	var errs []error
+	for _, cronJob := range cronJobs {
+		if !w.shouldSync(cronJob) && !hasFinalizer(cronJob) {
+			continue
+		}
+
+		key, err := cache.MetaNamespaceKeyFunc(cronJob)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		w.plan.beginSync(key)
+		if err := w.syncKey(withPlanKey(ctx, key), key); err != nil {
+			errs = append(errs, fmt.Errorf("CronJob %s: %w", key, err))
+		}
+	}
+
+	if w.opts.GC.Interval > 0 {
+		// A single pass cannot wait out the grace period, so plan every
+		// orphan as if it had passed
+		w.opts.GC.GracePeriod = 0
+		if err := w.collectGarbage(ctx, make(map[string]time.Time)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return w.plan, utilerrors.NewAggregate(errs)
+}
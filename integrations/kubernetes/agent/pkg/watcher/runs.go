@@ -0,0 +1,368 @@
+package watcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/saturn/k8s-agent/pkg/monitor"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// AnnotationReportRuns set to "false" on a CronJob stops the agent from
+	// reporting its runs, e.g. when the sidecar already does
+	AnnotationReportRuns = "saturn.co/report-runs"
+
+	// AnnotationRunState records on a Job which pings have been sent, so each
+	// run is reported once across agent restarts and leader changes
+	AnnotationRunState = "saturn.co/run-state"
+
+	runStateStarted  = "started"
+	runStateStalled  = "stalled"
+	runStateFinished = "finished"
+
+	// jobNameLabel is set by the Job controller on the pods it creates
+	jobNameLabel = "job-name"
+
+	// runReportLookback bounds how long ago a Job may have finished and still
+	// be reported when the agent first sees it. Older Jobs predate the agent.
+	runReportLookback = 15 * time.Minute
+)
+
+// setupRunReporting adds a Pod informer and feeds it and the Job informer into
+// a second work queue keyed by Job namespace/name
+func (w *CronJobWatcher) setupRunReporting() {
+	jobInformer := w.informerFactory.Batch().V1().Jobs()
+
+	// Only pods created by Jobs are cached
+	w.podInformerFactory = informers.NewSharedInformerFactoryWithOptions(
+		w.clientset,
+		w.opts.SyncPeriod,
+		informers.WithNamespace(w.opts.Namespace),
+		informers.WithTweakListOptions(func(opts *v1.ListOptions) {
+			opts.LabelSelector = jobNameLabel
+		}),
+	)
+	podInformer := w.podInformerFactory.Core().V1().Pods()
+
+	w.podLister = podInformer.Lister()
+	w.jobQueue = workqueue.NewRateLimitingQueueWithConfig(newRateLimiter(), workqueue.RateLimitingQueueConfig{Name: "jobs"})
+	w.synced = append(w.synced, podInformer.Informer().HasSynced)
+
+	jobInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    w.onJob,
+		UpdateFunc: func(_, obj interface{}) { w.onJob(obj) },
+	})
+	podInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    w.onPod,
+		UpdateFunc: func(_, obj interface{}) { w.onPod(obj) },
+	})
+}
+
+func (w *CronJobWatcher) onJob(obj interface{}) {
+	job, ok := obj.(*batchv1.Job)
+	if !ok {
+		return
+	}
+
+	if ref := v1.GetControllerOf(job); ref == nil || ref.Kind != "CronJob" {
+		return
+	}
+	if job.Annotations[AnnotationRunState] == runStateFinished {
+		return
+	}
+
+	w.jobQueue.Add(job.Namespace + "/" + job.Name)
+}
+
+func (w *CronJobWatcher) onPod(obj interface{}) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return
+	}
+
+	if ref := v1.GetControllerOf(pod); ref != nil && ref.Kind == "Job" {
+		w.jobQueue.Add(pod.Namespace + "/" + ref.Name)
+	}
+}
+
+func (w *CronJobWatcher) runJobWorker(ctx context.Context) {
+	for w.processNextJob(ctx) {
+	}
+}
+
+// processNextJob reports one Job from the queue, requeueing it with backoff on failure
+func (w *CronJobWatcher) processNextJob(ctx context.Context) bool {
+	item, quit := w.jobQueue.Get()
+	if quit {
+		return false
+	}
+	defer w.jobQueue.Done(item)
+
+	key := item.(string)
+
+	if err := w.syncJob(ctx, key); err != nil {
+		klog.Errorf("Failed to report run of Job %s (attempt %d): %v", key, w.jobQueue.NumRequeues(key)+1, err)
+		w.jobQueue.AddRateLimited(key)
+		return true
+	}
+
+	w.jobQueue.Forget(key)
+	return true
+}
+
+// syncJob sends the pings a Job's progress calls for: start once a pod has
+// started, then success or fail once the Job has finished
+func (w *CronJobWatcher) syncJob(ctx context.Context, key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+
+	job, err := w.jobLister.Jobs(namespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	state := job.Annotations[AnnotationRunState]
+	if state == runStateFinished {
+		return nil
+	}
+
+	cronJob := w.owningCronJob(job)
+	if cronJob == nil || !w.reportRuns(cronJob) {
+		return nil
+	}
+
+	monitorID := cronJob.Annotations[AnnotationMonitorID]
+	if monitorID == "" {
+		return fmt.Errorf("CronJob %s/%s has no monitor yet", cronJob.Namespace, cronJob.Name)
+	}
+
+	finished, succeeded, finishedAt := jobFinished(job)
+
+	if finished {
+		if state == "" && time.Since(finishedAt) > runReportLookback {
+			klog.V(4).Infof("Not reporting Job %s/%s, finished before the agent started", job.Namespace, job.Name)
+			return nil
+		}
+
+		if state == runStateStalled && !succeeded {
+			// The failure was already reported while the pod was stuck
+			return w.setRunState(ctx, job, runStateFinished)
+		}
+
+		ping := &monitor.Ping{State: monitor.PingSuccess}
+		if !succeeded {
+			ping.State = monitor.PingFail
+			exitCode := w.jobExitCode(job)
+			ping.ExitCode = &exitCode
+			ping.Failure = w.classifyFailure(ctx, job)
+		}
+		if job.Status.StartTime != nil {
+			durationMs := finishedAt.Sub(job.Status.StartTime.Time).Milliseconds()
+			ping.DurationMs = &durationMs
+		}
+
+		if err := w.sendPing(ctx, monitorID, job, ping); err != nil {
+			return err
+		}
+		return w.setRunState(ctx, job, runStateFinished)
+	}
+
+	if state != runStateStalled {
+		if failure := w.stuckFailure(job); failure != nil {
+			klog.Infof("Job %s/%s cannot start: %s", job.Namespace, job.Name, failure.Message)
+			if err := w.sendPing(ctx, monitorID, job, &monitor.Ping{State: monitor.PingFail, Failure: failure}); err != nil {
+				return err
+			}
+			return w.setRunState(ctx, job, runStateStalled)
+		}
+	}
+
+	if state == "" && w.jobStarted(job) {
+		if err := w.sendPing(ctx, monitorID, job, &monitor.Ping{State: monitor.PingStart}); err != nil {
+			return err
+		}
+		return w.setRunState(ctx, job, runStateStarted)
+	}
+
+	return nil
+}
+
+// owningCronJob returns the monitored CronJob that created job, if any
+func (w *CronJobWatcher) owningCronJob(job *batchv1.Job) *batchv1.CronJob {
+	ref := v1.GetControllerOf(job)
+	if ref == nil || ref.Kind != "CronJob" {
+		return nil
+	}
+
+	cronJob, err := w.lister.CronJobs(job.Namespace).Get(ref.Name)
+	if err != nil || cronJob.UID != ref.UID || !w.shouldSync(cronJob) {
+		return nil
+	}
+	return cronJob
+}
+
+// reportRuns reports whether runs of cronJob are reported by the agent
+func (w *CronJobWatcher) reportRuns(cronJob *batchv1.CronJob) bool {
+	return cronJob.Annotations[AnnotationReportRuns] != "false"
+}
+
+// jobFinished reports whether job has finished, whether it succeeded, and when
+func jobFinished(job *batchv1.Job) (finished, succeeded bool, at time.Time) {
+	for _, cond := range job.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+
+		switch cond.Type {
+		case batchv1.JobComplete:
+			at = cond.LastTransitionTime.Time
+			if job.Status.CompletionTime != nil {
+				at = job.Status.CompletionTime.Time
+			}
+			return true, true, at
+		case batchv1.JobFailed:
+			return true, false, cond.LastTransitionTime.Time
+		}
+	}
+	return false, false, time.Time{}
+}
+
+// jobPods returns the pods created by job
+func (w *CronJobWatcher) jobPods(job *batchv1.Job) []*corev1.Pod {
+	pods, err := w.podLister.Pods(job.Namespace).List(labels.SelectorFromSet(labels.Set{jobNameLabel: job.Name}))
+	if err != nil {
+		return nil
+	}
+
+	var owned []*corev1.Pod
+	for _, pod := range pods {
+		if ref := v1.GetControllerOf(pod); ref != nil && ref.UID == job.UID {
+			owned = append(owned, pod)
+		}
+	}
+	return owned
+}
+
+// jobStarted reports whether a container of any of job's pods has started
+func (w *CronJobWatcher) jobStarted(job *batchv1.Job) bool {
+	for _, pod := range w.jobPods(job) {
+		switch pod.Status.Phase {
+		case corev1.PodRunning, corev1.PodSucceeded, corev1.PodFailed:
+			return true
+		}
+	}
+	return false
+}
+
+// jobExitCode returns the exit code of the most recently terminated failing
+// container of job, or 1 if none is known
+func (w *CronJobWatcher) jobExitCode(job *batchv1.Job) int {
+	exitCode := 1
+	var latest time.Time
+
+	for _, pod := range w.jobPods(job) {
+		for _, status := range pod.Status.ContainerStatuses {
+			terminated := status.State.Terminated
+			if terminated == nil {
+				terminated = status.LastTerminationState.Terminated
+			}
+			if terminated == nil || terminated.ExitCode == 0 {
+				continue
+			}
+			if terminated.FinishedAt.Time.After(latest) {
+				latest = terminated.FinishedAt.Time
+				exitCode = int(terminated.ExitCode)
+			}
+		}
+	}
+	return exitCode
+}
+
+// sendPing reports ping to the monitor. Pings for disabled monitors are
+// dropped, since Saturn rejects them.
+func (w *CronJobWatcher) sendPing(ctx context.Context, monitorID string, job *batchv1.Job, ping *monitor.Ping) error {
+	m, err := w.runMonitor(ctx, monitorID)
+	if err != nil {
+		return err
+	}
+
+	if ping.State == monitor.PingFail && m.CaptureOutput != nil && *m.CaptureOutput {
+		ping.Output = w.failedPodLogs(ctx, job, captureBudget(m))
+	}
+
+	err = w.monitorManager.SendPing(ctx, m.Token, ping)
+	if errors.Is(err, monitor.ErrDisabled) {
+		klog.V(2).Infof("Monitor %s is disabled, dropping %s ping for Job %s/%s", monitorID, ping.State, job.Namespace, job.Name)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to send %s ping: %w", ping.State, err)
+	}
+
+	klog.V(2).Infof("Reported %s of Job %s/%s to monitor %s", ping.State, job.Namespace, job.Name, monitorID)
+	return nil
+}
+
+// setRunState records on job which pings have been sent
+func (w *CronJobWatcher) setRunState(ctx context.Context, job *batchv1.Job, state string) error {
+	patch := []byte(fmt.Sprintf(`{"metadata":{"annotations":{%q:%q}}}`, AnnotationRunState, state))
+
+	return retry.OnError(retry.DefaultBackoff, isRetriable, func() error {
+		_, err := w.clientset.BatchV1().Jobs(job.Namespace).Patch(ctx, job.Name, types.MergePatchType, patch, v1.PatchOptions{})
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	})
+}
+
+// storeMonitor caches the ping token and capture settings returned with a monitor
+func (w *CronJobWatcher) storeMonitor(m *monitor.Monitor) {
+	if m.Token == "" {
+		return
+	}
+
+	w.monitorsMu.Lock()
+	w.monitors[m.ID] = m
+	w.monitorsMu.Unlock()
+}
+
+// runMonitor returns the cached monitor used for pings, fetching it if needed
+func (w *CronJobWatcher) runMonitor(ctx context.Context, monitorID string) (*monitor.Monitor, error) {
+	w.monitorsMu.Lock()
+	m := w.monitors[monitorID]
+	w.monitorsMu.Unlock()
+
+	if m != nil {
+		return m, nil
+	}
+
+	m, err := w.monitorManager.GetMonitor(ctx, monitorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get monitor token: %w", err)
+	}
+	if m.Token == "" {
+		return nil, fmt.Errorf("monitor %s has no ping token", monitorID)
+	}
+
+	w.storeMonitor(m)
+	return m, nil
+}
@@ -0,0 +1,111 @@
+package watcher
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/saturn/k8s-agent/pkg/monitor"
+)
+
+// ReasonInvalidSchedule is the event reason reported for CronJobs whose
+// schedule cannot be turned into a monitor schedule
+const ReasonInvalidSchedule = "InvalidSchedule"
+
+// errInvalidSchedule marks schedule errors in buildMonitorSpec's result
+var errInvalidSchedule = errors.New("invalid schedule")
+
+// scheduleMacros maps the macros Kubernetes accepts to the cron expressions
+// they stand for
+var scheduleMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// monitorSchedule is a CronJob schedule in the form Saturn expects
+type monitorSchedule struct {
+	ScheduleType string
+	CronExpr     string
+	IntervalSec  int
+	Timezone     *string
+}
+
+// parseSchedule normalises a CronJob schedule: a CRON_TZ= or TZ= prefix
+// becomes the time zone, macros are expanded and @every becomes an interval.
+// timeZone is the CronJob's spec.timeZone.
+func parseSchedule(expr string, timeZone *string) (*monitorSchedule, error) {
+	fields := strings.Fields(expr)
+	result := &monitorSchedule{ScheduleType: "CRON", Timezone: timeZone}
+
+	if len(fields) > 0 && (strings.HasPrefix(fields[0], "CRON_TZ=") || strings.HasPrefix(fields[0], "TZ=")) {
+		tz := fields[0][strings.Index(fields[0], "=")+1:]
+		if _, err := time.LoadLocation(tz); err != nil || tz == "" {
+			return nil, fmt.Errorf("%w %q: unknown time zone %q", errInvalidSchedule, expr, tz)
+		}
+		if timeZone != nil && *timeZone != tz {
+			return nil, fmt.Errorf("%w %q: time zone %q conflicts with spec.timeZone %q", errInvalidSchedule, expr, tz, *timeZone)
+		}
+		result.Timezone = &tz
+		fields = fields[1:]
+	}
+
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("%w %q: empty schedule", errInvalidSchedule, expr)
+	}
+
+	if fields[0] == "@every" {
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%w %q: @every takes one duration", errInvalidSchedule, expr)
+		}
+		interval, err := time.ParseDuration(fields[1])
+		if err != nil || interval < time.Second {
+			return nil, fmt.Errorf("%w %q: @every needs a duration of at least 1s", errInvalidSchedule, expr)
+		}
+		result.ScheduleType = "INTERVAL"
+		result.IntervalSec = int(interval / time.Second)
+		return result, nil
+	}
+
+	if strings.HasPrefix(fields[0], "@") {
+		macro, ok := scheduleMacros[strings.ToLower(fields[0])]
+		if !ok || len(fields) != 1 {
+			return nil, fmt.Errorf("%w %q: unknown macro %s", errInvalidSchedule, expr, fields[0])
+		}
+		fields = strings.Fields(macro)
+	}
+
+	result.CronExpr = strings.Join(fields, " ")
+	if _, err := cron.ParseStandard(result.CronExpr); err != nil {
+		return nil, fmt.Errorf("%w %q: %v", errInvalidSchedule, expr, err)
+	}
+
+	return result, nil
+}
+
+// nextRun returns the first time after now that the monitor expects a run
+func nextRun(spec *monitor.MonitorSpec, now time.Time) (time.Time, error) {
+	if spec.ScheduleType == "INTERVAL" {
+		return now.Add(time.Duration(spec.IntervalSec) * time.Second), nil
+	}
+
+	schedule, err := cron.ParseStandard(spec.CronExpr)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid schedule %q: %w", spec.CronExpr, err)
+	}
+
+	loc := time.UTC
+	if spec.Timezone != nil && *spec.Timezone != "" {
+		if loc, err = time.LoadLocation(*spec.Timezone); err != nil {
+			return time.Time{}, fmt.Errorf("invalid time zone %q: %w", *spec.Timezone, err)
+		}
+	}
+
+	return schedule.Next(now.In(loc)), nil
+}
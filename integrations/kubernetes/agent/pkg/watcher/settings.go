@@ -0,0 +1,157 @@
+package watcher
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/saturn/k8s-agent/pkg/config"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/klog/v2"
+)
+
+// Settings are the parts of the agent configuration that can change while it
+// runs, from flags and the configuration file
+type Settings struct {
+	// Namespaces limits monitoring to these namespaces (empty = all);
+	// ExcludeNamespaces are never monitored
+	Namespaces        []string
+	ExcludeNamespaces []string
+
+	// Selector limits monitoring to CronJobs with matching labels (nil = all)
+	Selector labels.Selector
+
+	// DefaultGraceSec applies to CronJobs without saturn.co/grace-sec
+	DefaultGraceSec int
+
+	// DefaultTags are added to every monitor after the built-in tags
+	DefaultTags []string
+
+	// DeletionPolicy applies to monitors of deleted CronJobs unless
+	// overridden by the saturn.co/deletion-policy annotation
+	DeletionPolicy string
+
+	// NameTemplate renders monitor names (default DefaultNameTemplate)
+	NameTemplate *template.Template
+
+	// LabelTags copies CronJob labels into monitor tags
+	LabelTags []LabelTag
+}
+
+// ApplyFile returns base with the values set in file, validating all of them
+func ApplyFile(base Settings, file *config.File) (*Settings, error) {
+	s := base
+	var errs []error
+
+	if file.Namespaces != nil {
+		s.Namespaces = file.Namespaces
+	}
+	if file.ExcludeNamespaces != nil {
+		s.ExcludeNamespaces = file.ExcludeNamespaces
+	}
+	for _, ns := range append(append([]string{}, s.Namespaces...), s.ExcludeNamespaces...) {
+		if msgs := validation.IsDNS1123Label(ns); len(msgs) > 0 {
+			errs = append(errs, fmt.Errorf("namespace %q: %s", ns, strings.Join(msgs, "; ")))
+		}
+	}
+
+	if file.Selector != "" {
+		selector, err := labels.Parse(file.Selector)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("selector: %w", err))
+		}
+		s.Selector = selector
+	}
+
+	if file.Defaults.GraceSec < 0 {
+		errs = append(errs, fmt.Errorf("defaults.graceSec must be positive, got %d", file.Defaults.GraceSec))
+	} else if file.Defaults.GraceSec > 0 {
+		s.DefaultGraceSec = file.Defaults.GraceSec
+	}
+
+	if file.Defaults.Tags != nil {
+		s.DefaultTags = parseTags(strings.Join(file.Defaults.Tags, ","))
+	}
+
+	if file.DeletionPolicy != "" {
+		if err := ValidateDeletionPolicy(file.DeletionPolicy); err != nil {
+			errs = append(errs, fmt.Errorf("deletionPolicy: %w", err))
+		}
+		s.DeletionPolicy = file.DeletionPolicy
+	}
+
+	if file.NameTemplate != "" {
+		tmpl, err := ParseNameTemplate(file.NameTemplate)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("nameTemplate: %w", err))
+		}
+		s.NameTemplate = tmpl
+	}
+
+	if file.LabelTags != nil {
+		labelTags, err := ParseLabelTags(strings.Join(file.LabelTags, ","))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("labelTags: %w", err))
+		}
+		s.LabelTags = labelTags
+	}
+
+	if err := utilerrors.NewAggregate(errs); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// inScope reports whether cronJob is in a monitored namespace and matches
+// the selector
+func (s *Settings) inScope(cronJob *batchv1.CronJob) bool {
+	for _, ns := range s.ExcludeNamespaces {
+		if ns == cronJob.Namespace {
+			return false
+		}
+	}
+
+	if len(s.Namespaces) > 0 {
+		found := false
+		for _, ns := range s.Namespaces {
+			if ns == cronJob.Namespace {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return s.Selector == nil || s.Selector.Matches(labels.Set(cronJob.Labels))
+}
+
+// settings returns the settings in effect
+func (w *CronJobWatcher) settings() *Settings {
+	w.settingsMu.RLock()
+	defer w.settingsMu.RUnlock()
+	return w.current
+}
+
+// UpdateSettings swaps in new settings and queues every CronJob so monitors
+// pick them up without a restart
+func (w *CronJobWatcher) UpdateSettings(s *Settings) {
+	w.settingsMu.Lock()
+	w.current = s
+	w.settingsMu.Unlock()
+
+	cronJobs, err := w.lister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("Failed to list CronJobs after a settings change: %v", err)
+		return
+	}
+
+	for _, cronJob := range cronJobs {
+		w.enqueue(cronJob)
+	}
+	klog.Infof("Settings changed, resyncing %d CronJobs", len(cronJobs))
+}
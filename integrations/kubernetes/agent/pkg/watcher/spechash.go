@@ -0,0 +1,142 @@
+package watcher
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/saturn/k8s-agent/pkg/monitor"
+	batchv1 "k8s.io/api/batch/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+)
+
+// AnnotationSpecHash records on a CronJob the hash of the monitor state last
+// applied to Saturn, so unchanged CronJobs are not applied again after agent
+// restarts and leader changes
+const AnnotationSpecHash = "saturn.co/spec-hash"
+
+// appliedSpec is the desired monitor state last synced for a CronJob
+type appliedSpec struct {
+	hash string
+
+	// refreshAt is when the monitor is applied again even if unchanged
+	refreshAt time.Time
+}
+
+// specHash fingerprints the desired state of a CronJob's monitor: the monitor
+// it is linked to, the derived spec and whether the monitor should be disabled
+func specHash(monitorID string, spec *monitor.MonitorSpec, suspended bool) string {
+	desired := *spec
+	desired.IdempotencyKey = "" // only sent with creates
+
+	data, err := json.Marshal(struct {
+		MonitorID string
+		Spec      monitor.MonitorSpec
+		Suspended bool
+	}{monitorID, desired, suspended})
+	if err != nil {
+		// Cannot happen for plain data; an empty hash never matches
+		return ""
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:16])
+}
+
+// isSuspended reports whether cronJob has spec.suspend set
+func isSuspended(cronJob *batchv1.CronJob) bool {
+	return cronJob.Spec.Suspend != nil && *cronJob.Spec.Suspend
+}
+
+// upToDate reports whether cronJob, stored under key, was last synced with
+// the desired state hash, recently enough that it need not be applied again.
+// The hash is remembered in memory and in the CronJob's spec-hash annotation.
+// Dry runs change nothing, so they always sync.
+func (w *CronJobWatcher) upToDate(key string, cronJob *batchv1.CronJob, hash string) bool {
+	if w.plan != nil || hash == "" {
+		return false
+	}
+
+	w.appliedMu.Lock()
+	defer w.appliedMu.Unlock()
+
+	applied, ok := w.applied[key]
+	if !ok {
+		if cronJob.Annotations[AnnotationSpecHash] != hash {
+			return false
+		}
+
+		// Applied before this agent started. Spread the first refresh of
+		// these CronJobs over the interval instead of applying them all at once.
+		applied = appliedSpec{hash: hash, refreshAt: w.refreshAt(true)}
+		w.applied[key] = applied
+	}
+
+	if applied.hash != hash {
+		return false
+	}
+	return applied.refreshAt.IsZero() || time.Now().Before(applied.refreshAt)
+}
+
+// refreshAt returns when a monitor applied now is due to be applied again,
+// or the zero time if monitors are never refreshed
+func (w *CronJobWatcher) refreshAt(spread bool) time.Time {
+	interval := w.opts.MonitorRefreshInterval
+	if interval <= 0 {
+		return time.Time{}
+	}
+	if spread {
+		interval = time.Duration(rand.Int63n(int64(interval))) + 1
+	}
+	return time.Now().Add(interval)
+}
+
+// rememberApplied records a successful sync of cronJob, stored under key, in
+// memory and in its spec-hash annotation. Failing to write the annotation
+// only costs an extra sync after a restart, so it is logged and ignored.
+func (w *CronJobWatcher) rememberApplied(ctx context.Context, key string, cronJob *batchv1.CronJob, hash string) {
+	w.appliedMu.Lock()
+	w.applied[key] = appliedSpec{hash: hash, refreshAt: w.refreshAt(false)}
+	w.appliedMu.Unlock()
+
+	if w.plan != nil || cronJob.Annotations[AnnotationSpecHash] == hash {
+		return
+	}
+
+	patch := []byte(fmt.Sprintf(`{"metadata":{"annotations":{%q:%q}}}`, AnnotationSpecHash, hash))
+	_, err := w.clientset.BatchV1().CronJobs(cronJob.Namespace).Patch(ctx, cronJob.Name, types.MergePatchType, patch, v1.PatchOptions{})
+	if err != nil {
+		klog.Warningf("Failed to record spec hash on CronJob %s: %v", key, err)
+	}
+}
+
+// clearSpecHash removes the spec-hash annotation from a CronJob that is no
+// longer synced, so it is applied in full if monitoring is turned back on
+func (w *CronJobWatcher) clearSpecHash(ctx context.Context, cronJob *batchv1.CronJob) {
+	if w.plan != nil {
+		return
+	}
+	if _, ok := cronJob.Annotations[AnnotationSpecHash]; !ok {
+		return
+	}
+
+	patch := []byte(fmt.Sprintf(`{"metadata":{"annotations":{%q:null}}}`, AnnotationSpecHash))
+	_, err := w.clientset.BatchV1().CronJobs(cronJob.Namespace).Patch(ctx, cronJob.Name, types.MergePatchType, patch, v1.PatchOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		klog.Warningf("Failed to clear spec hash on CronJob %s/%s: %v", cronJob.Namespace, cronJob.Name, err)
+	}
+}
+
+// forgetApplied drops the synced state of a CronJob
+func (w *CronJobWatcher) forgetApplied(key string) {
+	w.appliedMu.Lock()
+	delete(w.applied, key)
+	w.appliedMu.Unlock()
+}
@@ -0,0 +1,50 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/saturn/k8s-agent/pkg/monitor"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+)
+
+// Event reasons for suspend and resume
+const (
+	ReasonMonitorDisabled = "MonitorDisabled"
+	ReasonMonitorEnabled  = "MonitorEnabled"
+)
+
+// syncSuspend mirrors spec.suspend into the monitor's status: a suspended
+// CronJob disables its monitor, and resuming enables it again with the next
+// run calculated from now so the suspended period is not reported as missed.
+func (w *CronJobWatcher) syncSuspend(ctx context.Context, cronJob *batchv1.CronJob, current *monitor.Monitor, spec *monitor.MonitorSpec) error {
+	suspended := isSuspended(cronJob)
+	disabled := current.Status == monitor.StatusDisabled
+
+	switch {
+	case suspended && !disabled:
+		klog.Infof("CronJob %s/%s is suspended, disabling monitor %s", cronJob.Namespace, cronJob.Name, current.ID)
+		if err := w.monitorManager.DisableMonitor(ctx, current.ID); err != nil {
+			return fmt.Errorf("failed to disable monitor: %w", err)
+		}
+		w.recorder.Eventf(cronJob, corev1.EventTypeNormal, ReasonMonitorDisabled, "Disabled Saturn monitor %s while the CronJob is suspended", current.ID)
+
+	case !suspended && disabled:
+		next, err := nextRun(spec, time.Now())
+		if err != nil {
+			// Let Saturn derive the next run from the schedule instead
+			klog.Warningf("Failed to calculate next run of CronJob %s/%s: %v", cronJob.Namespace, cronJob.Name, err)
+		}
+
+		klog.Infof("CronJob %s/%s is active, enabling monitor %s (next run %s)", cronJob.Namespace, cronJob.Name, current.ID, next)
+		if err := w.monitorManager.EnableMonitor(ctx, current.ID, next); err != nil {
+			return fmt.Errorf("failed to enable monitor: %w", err)
+		}
+		w.recorder.Eventf(cronJob, corev1.EventTypeNormal, ReasonMonitorEnabled, "Enabled Saturn monitor %s", current.ID)
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+package watcher
+
+import (
+	"fmt"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+)
+
+// LabelTag copies the value of a CronJob label into a "<tag>:<value>" tag
+type LabelTag struct {
+	Label string
+	Tag   string
+}
+
+// ParseLabelTags parses a comma-separated list of label keys, each optionally
+// renamed with "label=tag", e.g. "team,app.kubernetes.io/part-of=app"
+func ParseLabelTags(s string) ([]LabelTag, error) {
+	var result []LabelTag
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		label, tag, renamed := strings.Cut(item, "=")
+		label = strings.TrimSpace(label)
+		tag = strings.TrimSpace(tag)
+		if !renamed {
+			tag = label
+		}
+		if label == "" || tag == "" {
+			return nil, fmt.Errorf("invalid label mapping %q", item)
+		}
+
+		result = append(result, LabelTag{Label: label, Tag: tag})
+	}
+	return result, nil
+}
+
+// parseTags splits a comma-separated tag list, trimming whitespace around
+// tags and around the colon of key:value tags, and dropping empty entries
+func parseTags(s string) []string {
+	var tags []string
+	for _, tag := range strings.Split(s, ",") {
+		if key, value, ok := strings.Cut(tag, ":"); ok {
+			key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+			if key == "" || value == "" {
+				continue
+			}
+			tags = append(tags, key+":"+value)
+			continue
+		}
+
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// buildTags assembles the tags of the monitor for cronJob: built-in tags,
+// default tags, mapped labels, the saturn.co/tags annotation and ownership
+// markers, in that order and without duplicates
+func (w *CronJobWatcher) buildTags(cronJob *batchv1.CronJob) []string {
+	settings := w.settings()
+
+	tags := []string{
+		"kubernetes",
+		"cluster:" + w.opts.ClusterName,
+		"namespace:" + cronJob.Namespace,
+	}
+
+	tags = append(tags, settings.DefaultTags...)
+
+	for _, lt := range settings.LabelTags {
+		if value := cronJob.Labels[lt.Label]; value != "" {
+			tags = append(tags, lt.Tag+":"+value)
+		}
+	}
+
+	tags = append(tags, parseTags(cronJob.Annotations[AnnotationTags])...)
+	tags = append(tags, ownerTags(w.opts.ClusterName, cronJob)...)
+
+	return dedupe(tags)
+}
+
+func dedupe(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	result := values[:0]
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			result = append(result, v)
+		}
+	}
+	return result
+}
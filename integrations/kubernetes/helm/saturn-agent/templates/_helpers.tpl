@@ -59,3 +59,11 @@ Create the name of the service account to use
 {{- end }}
 {{- end }}
 
+{{/*
+Whether leader election is enabled
+*/}}
+{{- define "saturn-agent.leaderElect" -}}
+{{- if or .Values.leaderElection.enabled (gt (int .Values.replicaCount) 1) -}}
+true
+{{- end -}}
+{{- end }}
@@ -14,6 +14,13 @@ type Config struct {
 	MainContainerName string
 	CaptureOutput     bool
 	MaxOutputBytes    int
+
+	// TLS and proxy settings for requests to the Saturn API
+	CACertFile         string
+	ClientCertFile     string
+	ClientKeyFile      string
+	ProxyURL           string
+	InsecureSkipVerify bool
 }
 
 func loadConfig() (*Config, error) {
@@ -64,6 +71,12 @@ func loadConfig() (*Config, error) {
 		MainContainerName: mainContainerName,
 		CaptureOutput:     captureOutput,
 		MaxOutputBytes:    maxOutputBytes,
+
+		CACertFile:         os.Getenv("PULSEGUARD_CA_CERT"),
+		ClientCertFile:     os.Getenv("PULSEGUARD_CLIENT_CERT"),
+		ClientKeyFile:      os.Getenv("PULSEGUARD_CLIENT_KEY"),
+		ProxyURL:           os.Getenv("PULSEGUARD_PROXY_URL"),
+		InsecureSkipVerify: os.Getenv("PULSEGUARD_INSECURE_SKIP_VERIFY") == "true",
 	}, nil
 }
 
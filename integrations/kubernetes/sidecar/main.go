@@ -24,6 +24,11 @@ func main() {
 	log.Printf("Monitoring CronJob: %s", config.CronJobName)
 	log.Printf("Saturn API: %s", config.SaturnAPI)
 
+	httpClient, err := newHTTPClient(config)
+	if err != nil {
+		log.Fatalf("Failed to configure HTTP client: %v", err)
+	}
+
 	// Create context for graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -39,7 +44,7 @@ func main() {
 	}()
 
 	// Send start ping
-	if err := sendPing(config, "start", 0, "", nil); err != nil {
+	if err := sendPing(httpClient, config, "start", 0, "", nil); err != nil {
 		log.Printf("Warning: Failed to send start ping: %v", err)
 	} else {
 		log.Println("✓ Start ping sent")
@@ -71,7 +76,7 @@ func main() {
 	}
 
 	// Send final ping with output
-	if err := sendPing(config, state, exitCode, output, &durationMs); err != nil {
+	if err := sendPing(httpClient, config, state, exitCode, output, &durationMs); err != nil {
 		log.Printf("Error sending %s ping: %v", state, err)
 		os.Exit(1)
 	}
@@ -2,15 +2,81 @@ package main
 
 import (
 	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"os"
 	"time"
 )
 
+// newHTTPClient builds the client used for all pings from the PULSEGUARD_*
+// TLS and proxy variables. Pings are short, so the timeout is lower than the
+// agent's.
+func newHTTPClient(config *Config) (*http.Client, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: config.InsecureSkipVerify,
+	}
+
+	// Extra CAs extend the system roots rather than replace them
+	if config.CACertFile != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+
+		pem, err := os.ReadFile(config.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("PULSEGUARD_CA_CERT: %w", err)
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("PULSEGUARD_CA_CERT: %s has no PEM certificates", config.CACertFile)
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	switch {
+	case config.ClientCertFile == "" && config.ClientKeyFile == "":
+		// No mutual TLS
+	case config.ClientCertFile == "" || config.ClientKeyFile == "":
+		return nil, errors.New("PULSEGUARD_CLIENT_CERT and PULSEGUARD_CLIENT_KEY must both be set for mutual TLS")
+	default:
+		cert, err := tls.LoadX509KeyPair(config.ClientCertFile, config.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("PULSEGUARD_CLIENT_CERT/PULSEGUARD_CLIENT_KEY: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	// Unset, HTTPS_PROXY and NO_PROXY from the pod environment apply
+	if config.ProxyURL != "" {
+		proxy, err := url.Parse(config.ProxyURL)
+		if err != nil {
+			// Keep only the reason so a proxy password never reaches the job log
+			if urlErr, ok := err.(*url.Error); ok {
+				err = urlErr.Err
+			}
+			return nil, fmt.Errorf("PULSEGUARD_PROXY_URL is malformed: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	return &http.Client{
+		Timeout:   10 * time.Second,
+		Transport: transport,
+	}, nil
+}
+
 // Send ping to Saturn API
-func sendPing(config *Config, state string, exitCode int, output string, durationMs *int) error {
+func sendPing(client *http.Client, config *Config, state string, exitCode int, output string, durationMs *int) error {
 	// Build URL with query parameters
 	pingURL := fmt.Sprintf("%s/api/ping/%s", config.SaturnAPI, config.MonitorToken)
 	
@@ -50,11 +116,6 @@ func sendPing(config *Config, state string, exitCode int, output string, duratio
 
 	req.Header.Set("User-Agent", "Saturn-K8s-Sidecar/1.0")
 
-	// Send request with timeout
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-	}
-
 	resp, err := client.Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to send ping: %w", err)
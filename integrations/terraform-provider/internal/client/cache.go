@@ -0,0 +1,203 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/sync/singleflight"
+)
+
+// readCache serves Get* calls for a provider instance. The first read of a
+// resource type prefetches every object of that type with one paginated list;
+// later reads are answered from memory. Writes drop the affected object, and
+// concurrent identical requests share a single API call.
+type readCache struct {
+	mu      sync.Mutex
+	loaded  map[string]bool
+	entries map[string]map[string][]byte
+	group   singleflight.Group
+
+	// writes counts invalidations; written records the count at the last
+	// write of each kind/id, so a read that started before that write cannot
+	// store what it fetched
+	writes  uint64
+	written map[string]uint64
+}
+
+// fetched is a response together with the write count when it was requested.
+type fetched struct {
+	data  []byte
+	since uint64
+}
+
+// listKeys maps API collection paths to the key holding items in list responses.
+var listKeys = map[string]string{
+	"monitors":     "monitors",
+	"alert-rules":  "alertRules",
+	"integrations": "integrations",
+	"status-pages": "statusPages",
+}
+
+func newReadCache() *readCache {
+	return &readCache{
+		loaded:  map[string]bool{},
+		entries: map[string]map[string][]byte{},
+		written: map[string]uint64{},
+	}
+}
+
+// EnableReadCache turns on read-through caching and list prefetch for this client.
+func (c *Client) EnableReadCache() {
+	c.cache = newReadCache()
+}
+
+// getObject fetches /api/<kind>/<id>, using the read cache when it is enabled.
+func (c *Client) getObject(ctx context.Context, kind, id string) ([]byte, error) {
+	path := "/api/" + kind + "/" + id
+
+	if c.cache == nil {
+		return c.DoRequest(ctx, "GET", path, nil)
+	}
+
+	c.prefetch(ctx, kind)
+
+	if data, ok := c.cache.get(kind, id); ok {
+		tflog.SubsystemTrace(ctx, logSubsystem, "Read served from cache", map[string]interface{}{
+			"http_path": path,
+		})
+		return data, nil
+	}
+
+	v, err, _ := c.cache.group.Do("GET "+path, func() (interface{}, error) {
+		since := c.cache.writeCount()
+		data, err := c.DoRequest(ctx, "GET", path, nil)
+		return fetched{data: data, since: since}, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := v.(fetched)
+	c.cache.put(kind, id, result.data, result.since)
+	return result.data, nil
+}
+
+// prefetch loads every object of kind into the cache once. Failures are logged
+// and reads fall back to individual requests. A truncated list is cached as far
+// as it goes, and objects beyond it are read individually.
+func (c *Client) prefetch(ctx context.Context, kind string) {
+	if c.cache.isLoaded(kind) {
+		return
+	}
+
+	_, _, _ = c.cache.group.Do("LIST "+kind, func() (interface{}, error) {
+		if c.cache.isLoaded(kind) {
+			return nil, nil
+		}
+
+		since := c.cache.writeCount()
+		items, err := listRaw(ctx, c, "/api/"+kind, listKeys[kind])
+		if errors.Is(err, ErrListTruncated) {
+			tflog.SubsystemWarn(ctx, logSubsystem, "Cache prefetch got a truncated list, objects beyond it are read individually", map[string]interface{}{
+				"kind":  kind,
+				"count": len(items),
+				"error": err.Error(),
+			})
+		} else if err != nil {
+			tflog.SubsystemWarn(ctx, logSubsystem, "Cache prefetch failed, reading objects individually", map[string]interface{}{
+				"kind":  kind,
+				"error": err.Error(),
+			})
+		}
+
+		objects := make(map[string][]byte, len(items))
+		for _, item := range items {
+			var ref struct {
+				ID string `json:"id"`
+			}
+			if json.Unmarshal(item, &ref) == nil && ref.ID != "" {
+				objects[ref.ID] = item
+			}
+		}
+
+		c.cache.load(kind, objects, since)
+		tflog.SubsystemDebug(ctx, logSubsystem, "Prefetched objects into cache", map[string]interface{}{
+			"kind":  kind,
+			"count": len(objects),
+		})
+		return nil, nil
+	})
+}
+
+// invalidate drops cached state affected by a write to path.
+func (rc *readCache) invalidate(path string) {
+	path = strings.SplitN(path, "?", 2)[0]
+	parts := strings.Split(strings.TrimPrefix(path, "/api/"), "/")
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if len(parts) >= 2 {
+		rc.writes++
+		rc.written[parts[0]+"/"+parts[1]] = rc.writes
+		delete(rc.entries[parts[0]], parts[1])
+	}
+}
+
+func (rc *readCache) writeCount() uint64 {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return rc.writes
+}
+
+// stale reports whether kind/id was written after a read that started at since.
+// The caller holds rc.mu.
+func (rc *readCache) stale(kind, id string, since uint64) bool {
+	return rc.written[kind+"/"+id] > since
+}
+
+func (rc *readCache) isLoaded(kind string) bool {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return rc.loaded[kind]
+}
+
+func (rc *readCache) load(kind string, objects map[string][]byte, since uint64) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if rc.entries[kind] == nil {
+		rc.entries[kind] = map[string][]byte{}
+	}
+	for id, data := range objects {
+		if !rc.stale(kind, id, since) {
+			rc.entries[kind][id] = data
+		}
+	}
+	rc.loaded[kind] = true
+}
+
+func (rc *readCache) get(kind, id string) ([]byte, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	data, ok := rc.entries[kind][id]
+	return data, ok
+}
+
+func (rc *readCache) put(kind, id string, data []byte, since uint64) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if rc.stale(kind, id, since) {
+		return
+	}
+
+	if rc.entries[kind] == nil {
+		rc.entries[kind] = map[string][]byte{}
+	}
+	rc.entries[kind][id] = data
+}
@@ -2,10 +2,14 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	neturl "net/url"
+	"strconv"
 	"time"
 )
 
@@ -14,6 +18,9 @@ type Client struct {
 	HTTPClient *http.Client
 	Endpoint   string
 	APIKey     string
+	MaxRetries int
+
+	cache *readCache
 }
 
 // NewClient -
@@ -22,46 +29,206 @@ func NewClient(endpoint, apiKey string) *Client {
 		HTTPClient: &http.Client{Timeout: 30 * time.Second},
 		Endpoint:   endpoint,
 		APIKey:     apiKey,
+		MaxRetries: 3,
 	}
 }
 
-// DoRequest -
-func (c *Client) DoRequest(method, path string, body interface{}) ([]byte, error) {
+// DoRequest sends an API request and returns the response body. Every attempt is
+// logged to the "client" tflog subsystem with credentials and secrets redacted.
+func (c *Client) DoRequest(ctx context.Context, method, path string, body interface{}) ([]byte, error) {
 	url := fmt.Sprintf("%s%s", c.Endpoint, path)
 
-	var reqBody io.Reader
+	var jsonBody []byte
 	if body != nil {
-		jsonBody, err := json.Marshal(body)
+		var err error
+		jsonBody, err = json.Marshal(body)
 		if err != nil {
 			return nil, err
 		}
-		reqBody = bytes.NewBuffer(jsonBody)
 	}
 
-	req, err := http.NewRequest(method, url, reqBody)
-	if err != nil {
-		return nil, err
+	requestID := newRequestID()
+	ctx = newLogContext(ctx, method, path, requestID)
+
+	for attempt := 0; ; attempt++ {
+		var reqBody io.Reader
+		if jsonBody != nil {
+			reqBody = bytes.NewReader(jsonBody)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.APIKey))
+		req.Header.Set("X-Request-ID", requestID)
+
+		logRequest(ctx, req, jsonBody, attempt)
+
+		start := time.Now()
+		resp, err := c.HTTPClient.Do(req)
+		latency := time.Since(start)
+
+		if err != nil {
+			logTransportError(ctx, err, latency, attempt)
+
+			if attempt < c.MaxRetries && isIdempotent(method) && ctx.Err() == nil {
+				if err := sleepContext(ctx, retryDelay(nil, attempt)); err != nil {
+					return nil, err
+				}
+				continue
+			}
+
+			return nil, err
+		}
+
+		respBody, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		responseID := resp.Header.Get("X-Request-ID")
+		if responseID == "" {
+			responseID = requestID
+		}
+
+		logResponse(ctx, resp, respBody, latency, attempt, responseID)
+
+		if attempt < c.MaxRetries && shouldRetry(method, resp.StatusCode) {
+			if err := sleepContext(ctx, retryDelay(resp, attempt)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return nil, fmt.Errorf("API request failed with status %d (request ID %s): %s", resp.StatusCode, responseID, string(respBody))
+		}
+
+		// Only a write the API accepted changes what reads should see
+		if c.cache != nil && method != "GET" {
+			c.cache.invalidate(path)
+		}
+
+		return respBody, nil
 	}
+}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.APIKey))
+// shouldRetry reports whether a response is worth retrying. Rate limited
+// requests were never processed; server errors are only retried when the
+// method is safe to repeat.
+func shouldRetry(method string, status int) bool {
+	if status == http.StatusTooManyRequests {
+		return true
+	}
+	return status >= 500 && isIdempotent(method)
+}
 
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, err
+func isIdempotent(method string) bool {
+	return method == "GET" || method == "PUT" || method == "DELETE"
+}
+
+// retryDelay honours Retry-After when present and otherwise backs off exponentially.
+func retryDelay(resp *http.Response, attempt int) time.Duration {
+	if resp != nil {
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs > 0 {
+			return time.Duration(secs) * time.Second
+		}
 	}
-	defer resp.Body.Close()
+	return time.Duration(1<<attempt) * time.Second
+}
 
-	respBody, err := io.ReadAll(resp.Body)
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// listPageSize is the number of items requested per list page
+const listPageSize = 100
+
+// ErrListTruncated is returned when a list endpoint sends a full page without
+// a cursor to the next one, so the items returned may not be all of them.
+var ErrListTruncated = errors.New("list may be truncated: a full page came back without a next cursor")
+
+// listAll follows cursor pagination on a list endpoint and decodes every item.
+func listAll[T any](ctx context.Context, c *Client, path, key string) ([]T, error) {
+	items, err := listRaw(ctx, c, path, key)
 	if err != nil {
 		return nil, err
 	}
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(respBody))
+	all := make([]T, 0, len(items))
+	for _, item := range items {
+		var v T
+		if err := json.Unmarshal(item, &v); err != nil {
+			return nil, err
+		}
+		all = append(all, v)
 	}
 
-	return respBody, nil
+	return all, nil
+}
+
+// listRaw follows cursor pagination on a list endpoint. Pages are either a bare
+// JSON array or an object holding the items under key plus a nextCursor. When
+// the list looks truncated it returns the items read so far together with an
+// error wrapping ErrListTruncated.
+func listRaw(ctx context.Context, c *Client, path, key string) ([]json.RawMessage, error) {
+	var all []json.RawMessage
+	cursor := ""
+
+	for {
+		query := neturl.Values{}
+		query.Set("limit", strconv.Itoa(listPageSize))
+		if cursor != "" {
+			query.Set("cursor", cursor)
+		}
+
+		data, err := c.DoRequest(ctx, "GET", path+"?"+query.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var page []json.RawMessage
+		next := ""
+
+		if err := json.Unmarshal(data, &page); err != nil {
+			var envelope map[string]json.RawMessage
+			if err := json.Unmarshal(data, &envelope); err != nil {
+				return nil, err
+			}
+			items, ok := envelope[key]
+			if !ok {
+				return nil, fmt.Errorf("list response from %s has no %q field", path, key)
+			}
+			if err := json.Unmarshal(items, &page); err != nil {
+				return nil, err
+			}
+			if raw, ok := envelope["nextCursor"]; ok {
+				_ = json.Unmarshal(raw, &next)
+			}
+		}
+
+		all = append(all, page...)
+
+		if next == "" && len(page) >= listPageSize {
+			return all, fmt.Errorf("%s: %w after %d items", path, ErrListTruncated, len(all))
+		}
+		if next == "" || len(page) == 0 {
+			return all, nil
+		}
+		cursor = next
+	}
 }
 
 // Monitor represents a monitor resource
@@ -78,8 +245,8 @@ type Monitor struct {
 }
 
 // CreateMonitor creates a new monitor
-func (c *Client) CreateMonitor(monitor *Monitor) (*Monitor, error) {
-	data, err := c.DoRequest("POST", "/api/monitors", monitor)
+func (c *Client) CreateMonitor(ctx context.Context, monitor *Monitor) (*Monitor, error) {
+	data, err := c.DoRequest(ctx, "POST", "/api/monitors", monitor)
 	if err != nil {
 		return nil, err
 	}
@@ -93,8 +260,8 @@ func (c *Client) CreateMonitor(monitor *Monitor) (*Monitor, error) {
 }
 
 // GetMonitor retrieves a monitor by ID
-func (c *Client) GetMonitor(id string) (*Monitor, error) {
-	data, err := c.DoRequest("GET", fmt.Sprintf("/api/monitors/%s", id), nil)
+func (c *Client) GetMonitor(ctx context.Context, id string) (*Monitor, error) {
+	data, err := c.getObject(ctx, "monitors", id)
 	if err != nil {
 		return nil, err
 	}
@@ -108,8 +275,8 @@ func (c *Client) GetMonitor(id string) (*Monitor, error) {
 }
 
 // UpdateMonitor updates an existing monitor
-func (c *Client) UpdateMonitor(id string, monitor *Monitor) (*Monitor, error) {
-	data, err := c.DoRequest("PATCH", fmt.Sprintf("/api/monitors/%s", id), monitor)
+func (c *Client) UpdateMonitor(ctx context.Context, id string, monitor *Monitor) (*Monitor, error) {
+	data, err := c.DoRequest(ctx, "PATCH", fmt.Sprintf("/api/monitors/%s", id), monitor)
 	if err != nil {
 		return nil, err
 	}
@@ -123,11 +290,16 @@ func (c *Client) UpdateMonitor(id string, monitor *Monitor) (*Monitor, error) {
 }
 
 // DeleteMonitor deletes a monitor
-func (c *Client) DeleteMonitor(id string) error {
-	_, err := c.DoRequest("DELETE", fmt.Sprintf("/api/monitors/%s", id), nil)
+func (c *Client) DeleteMonitor(ctx context.Context, id string) error {
+	_, err := c.DoRequest(ctx, "DELETE", fmt.Sprintf("/api/monitors/%s", id), nil)
 	return err
 }
 
+// ListMonitors retrieves all monitors
+func (c *Client) ListMonitors(ctx context.Context) ([]Monitor, error) {
+	return listAll[Monitor](ctx, c, "/api/monitors", "monitors")
+}
+
 // AlertRule represents an alert rule resource
 type AlertRule struct {
 	ID            string   `json:"id,omitempty"`
@@ -139,8 +311,8 @@ type AlertRule struct {
 }
 
 // CreateAlertRule creates a new alert rule
-func (c *Client) CreateAlertRule(rule *AlertRule) (*AlertRule, error) {
-	data, err := c.DoRequest("POST", "/api/alert-rules", rule)
+func (c *Client) CreateAlertRule(ctx context.Context, rule *AlertRule) (*AlertRule, error) {
+	data, err := c.DoRequest(ctx, "POST", "/api/alert-rules", rule)
 	if err != nil {
 		return nil, err
 	}
@@ -154,8 +326,8 @@ func (c *Client) CreateAlertRule(rule *AlertRule) (*AlertRule, error) {
 }
 
 // GetAlertRule retrieves an alert rule by ID
-func (c *Client) GetAlertRule(id string) (*AlertRule, error) {
-	data, err := c.DoRequest("GET", fmt.Sprintf("/api/alert-rules/%s", id), nil)
+func (c *Client) GetAlertRule(ctx context.Context, id string) (*AlertRule, error) {
+	data, err := c.getObject(ctx, "alert-rules", id)
 	if err != nil {
 		return nil, err
 	}
@@ -169,8 +341,8 @@ func (c *Client) GetAlertRule(id string) (*AlertRule, error) {
 }
 
 // UpdateAlertRule updates an existing alert rule
-func (c *Client) UpdateAlertRule(id string, rule *AlertRule) (*AlertRule, error) {
-	data, err := c.DoRequest("PATCH", fmt.Sprintf("/api/alert-rules/%s", id), rule)
+func (c *Client) UpdateAlertRule(ctx context.Context, id string, rule *AlertRule) (*AlertRule, error) {
+	data, err := c.DoRequest(ctx, "PATCH", fmt.Sprintf("/api/alert-rules/%s", id), rule)
 	if err != nil {
 		return nil, err
 	}
@@ -184,11 +356,16 @@ func (c *Client) UpdateAlertRule(id string, rule *AlertRule) (*AlertRule, error)
 }
 
 // DeleteAlertRule deletes an alert rule
-func (c *Client) DeleteAlertRule(id string) error {
-	_, err := c.DoRequest("DELETE", fmt.Sprintf("/api/alert-rules/%s", id), nil)
+func (c *Client) DeleteAlertRule(ctx context.Context, id string) error {
+	_, err := c.DoRequest(ctx, "DELETE", fmt.Sprintf("/api/alert-rules/%s", id), nil)
 	return err
 }
 
+// ListAlertRules retrieves all alert rules
+func (c *Client) ListAlertRules(ctx context.Context) ([]AlertRule, error) {
+	return listAll[AlertRule](ctx, c, "/api/alert-rules", "alertRules")
+}
+
 // Integration represents an integration resource
 type Integration struct {
 	ID     string                 `json:"id,omitempty"`
@@ -198,8 +375,8 @@ type Integration struct {
 }
 
 // CreateIntegration creates a new integration
-func (c *Client) CreateIntegration(integration *Integration) (*Integration, error) {
-	data, err := c.DoRequest("POST", "/api/integrations", integration)
+func (c *Client) CreateIntegration(ctx context.Context, integration *Integration) (*Integration, error) {
+	data, err := c.DoRequest(ctx, "POST", "/api/integrations", integration)
 	if err != nil {
 		return nil, err
 	}
@@ -213,8 +390,8 @@ func (c *Client) CreateIntegration(integration *Integration) (*Integration, erro
 }
 
 // GetIntegration retrieves an integration by ID
-func (c *Client) GetIntegration(id string) (*Integration, error) {
-	data, err := c.DoRequest("GET", fmt.Sprintf("/api/integrations/%s", id), nil)
+func (c *Client) GetIntegration(ctx context.Context, id string) (*Integration, error) {
+	data, err := c.getObject(ctx, "integrations", id)
 	if err != nil {
 		return nil, err
 	}
@@ -228,8 +405,8 @@ func (c *Client) GetIntegration(id string) (*Integration, error) {
 }
 
 // UpdateIntegration updates an existing integration
-func (c *Client) UpdateIntegration(id string, integration *Integration) (*Integration, error) {
-	data, err := c.DoRequest("PATCH", fmt.Sprintf("/api/integrations/%s", id), integration)
+func (c *Client) UpdateIntegration(ctx context.Context, id string, integration *Integration) (*Integration, error) {
+	data, err := c.DoRequest(ctx, "PATCH", fmt.Sprintf("/api/integrations/%s", id), integration)
 	if err != nil {
 		return nil, err
 	}
@@ -243,11 +420,16 @@ func (c *Client) UpdateIntegration(id string, integration *Integration) (*Integr
 }
 
 // DeleteIntegration deletes an integration
-func (c *Client) DeleteIntegration(id string) error {
-	_, err := c.DoRequest("DELETE", fmt.Sprintf("/api/integrations/%s", id), nil)
+func (c *Client) DeleteIntegration(ctx context.Context, id string) error {
+	_, err := c.DoRequest(ctx, "DELETE", fmt.Sprintf("/api/integrations/%s", id), nil)
 	return err
 }
 
+// ListIntegrations retrieves all integrations
+func (c *Client) ListIntegrations(ctx context.Context) ([]Integration, error) {
+	return listAll[Integration](ctx, c, "/api/integrations", "integrations")
+}
+
 // StatusPage represents a status page resource
 type StatusPage struct {
 	ID           string                 `json:"id,omitempty"`
@@ -260,8 +442,8 @@ type StatusPage struct {
 }
 
 // CreateStatusPage creates a new status page
-func (c *Client) CreateStatusPage(page *StatusPage) (*StatusPage, error) {
-	data, err := c.DoRequest("POST", "/api/status-pages", page)
+func (c *Client) CreateStatusPage(ctx context.Context, page *StatusPage) (*StatusPage, error) {
+	data, err := c.DoRequest(ctx, "POST", "/api/status-pages", page)
 	if err != nil {
 		return nil, err
 	}
@@ -275,8 +457,8 @@ func (c *Client) CreateStatusPage(page *StatusPage) (*StatusPage, error) {
 }
 
 // GetStatusPage retrieves a status page by ID
-func (c *Client) GetStatusPage(id string) (*StatusPage, error) {
-	data, err := c.DoRequest("GET", fmt.Sprintf("/api/status-pages/%s", id), nil)
+func (c *Client) GetStatusPage(ctx context.Context, id string) (*StatusPage, error) {
+	data, err := c.getObject(ctx, "status-pages", id)
 	if err != nil {
 		return nil, err
 	}
@@ -290,8 +472,8 @@ func (c *Client) GetStatusPage(id string) (*StatusPage, error) {
 }
 
 // UpdateStatusPage updates an existing status page
-func (c *Client) UpdateStatusPage(id string, page *StatusPage) (*StatusPage, error) {
-	data, err := c.DoRequest("PATCH", fmt.Sprintf("/api/status-pages/%s", id), page)
+func (c *Client) UpdateStatusPage(ctx context.Context, id string, page *StatusPage) (*StatusPage, error) {
+	data, err := c.DoRequest(ctx, "PATCH", fmt.Sprintf("/api/status-pages/%s", id), page)
 	if err != nil {
 		return nil, err
 	}
@@ -305,8 +487,44 @@ func (c *Client) UpdateStatusPage(id string, page *StatusPage) (*StatusPage, err
 }
 
 // DeleteStatusPage deletes a status page
-func (c *Client) DeleteStatusPage(id string) error {
-	_, err := c.DoRequest("DELETE", fmt.Sprintf("/api/status-pages/%s", id), nil)
+func (c *Client) DeleteStatusPage(ctx context.Context, id string) error {
+	_, err := c.DoRequest(ctx, "DELETE", fmt.Sprintf("/api/status-pages/%s", id), nil)
 	return err
 }
 
+// ListStatusPages retrieves all status pages
+func (c *Client) ListStatusPages(ctx context.Context) ([]StatusPage, error) {
+	return listAll[StatusPage](ctx, c, "/api/status-pages", "statusPages")
+}
+
+// DomainVerification represents the DNS and certificate state of a status page custom domain
+type DomainVerification struct {
+	Verified      bool   `json:"verified"`
+	CNAMERecord   string `json:"cnameRecord,omitempty"`
+	ExpectedCNAME string `json:"expectedCname"`
+	TXTRecord     string `json:"txtRecord,omitempty"`
+	ExpectedTXT   string `json:"expectedTxt"`
+	TLSStatus     string `json:"tlsStatus,omitempty"`
+	Error         string `json:"error,omitempty"`
+}
+
+// Ready reports whether the domain is verified and its certificate has been issued.
+// Deployments that terminate TLS elsewhere do not report a TLS status.
+func (v *DomainVerification) Ready() bool {
+	return v.Verified && (v.TLSStatus == "" || v.TLSStatus == "ISSUED")
+}
+
+// VerifyStatusPageDomain checks the custom domain of a status page
+func (c *Client) VerifyStatusPageDomain(ctx context.Context, id string) (*DomainVerification, error) {
+	data, err := c.DoRequest(ctx, "POST", fmt.Sprintf("/api/status-pages/%s/verify-domain", id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result DomainVerification
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
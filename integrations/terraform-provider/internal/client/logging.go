@@ -0,0 +1,159 @@
+package client
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// logSubsystem is the tflog subsystem for API traffic. Its level can be set
+// independently with TF_LOG_PROVIDER_SATURN_CLIENT.
+const logSubsystem = "client"
+
+const redacted = "***"
+
+// sensitiveKeys are header names and JSON field names (compared lowercased,
+// without underscores or dashes) whose values are never logged.
+var sensitiveKeys = map[string]bool{
+	"authorization":  true,
+	"apikey":         true,
+	"token":          true,
+	"secret":         true,
+	"password":       true,
+	"url":            true,
+	"webhookurl":     true,
+	"routingkey":     true,
+	"integrationkey": true,
+}
+
+// sensitivePatterns catch secrets that end up inside free-form strings such
+// as API error messages.
+var sensitivePatterns = []*regexp.Regexp{
+	regexp.MustCompile(`Bearer\s+[^\s"]+`),
+	regexp.MustCompile(`https://hooks\.slack\.com/[^\s"]+`),
+	regexp.MustCompile(`https://(discord|discordapp)\.com/api/webhooks/[^\s"]+`),
+}
+
+// newLogContext attaches the client subsystem and per-request fields to ctx.
+func newLogContext(ctx context.Context, method, path, requestID string) context.Context {
+	ctx = tflog.NewSubsystem(ctx, logSubsystem, tflog.WithLevelFromEnv("TF_LOG_PROVIDER_SATURN_CLIENT"))
+	ctx = tflog.SubsystemMaskAllFieldValuesRegexes(ctx, logSubsystem, sensitivePatterns...)
+	ctx = tflog.SubsystemMaskMessageRegexes(ctx, logSubsystem, sensitivePatterns...)
+	ctx = tflog.SubsystemSetField(ctx, logSubsystem, "http_method", method)
+	ctx = tflog.SubsystemSetField(ctx, logSubsystem, "http_path", path)
+	ctx = tflog.SubsystemSetField(ctx, logSubsystem, "request_id", requestID)
+	return ctx
+}
+
+func logRequest(ctx context.Context, req *http.Request, body []byte, attempt int) {
+	tflog.SubsystemDebug(ctx, logSubsystem, "Sending API request", map[string]interface{}{
+		"retry_count": attempt,
+	})
+	tflog.SubsystemTrace(ctx, logSubsystem, "API request details", map[string]interface{}{
+		"http_request_headers": redactHeaders(req.Header),
+		"http_request_body":    redactBody(body),
+	})
+}
+
+func logResponse(ctx context.Context, resp *http.Response, body []byte, latency time.Duration, attempt int, responseID string) {
+	fields := map[string]interface{}{
+		"http_status": resp.StatusCode,
+		"latency_ms":  latency.Milliseconds(),
+		"retry_count": attempt,
+		"request_id":  responseID,
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		fields["http_response_body"] = redactBody(body)
+		tflog.SubsystemWarn(ctx, logSubsystem, "API request failed", fields)
+	} else {
+		tflog.SubsystemDebug(ctx, logSubsystem, "Received API response", fields)
+	}
+
+	tflog.SubsystemTrace(ctx, logSubsystem, "API response details", map[string]interface{}{
+		"http_response_headers": redactHeaders(resp.Header),
+		"http_response_body":    redactBody(body),
+	})
+}
+
+func logTransportError(ctx context.Context, err error, latency time.Duration, attempt int) {
+	tflog.SubsystemWarn(ctx, logSubsystem, "API request error", map[string]interface{}{
+		"error":       err.Error(),
+		"latency_ms":  latency.Milliseconds(),
+		"retry_count": attempt,
+	})
+}
+
+// IsSensitiveField reports whether a header or config field holds a secret.
+func IsSensitiveField(key string) bool {
+	key = strings.ToLower(key)
+	key = strings.NewReplacer("_", "", "-", "").Replace(key)
+	return sensitiveKeys[key]
+}
+
+func redactHeaders(header http.Header) map[string]string {
+	result := make(map[string]string, len(header))
+	for name, values := range header {
+		if IsSensitiveField(name) {
+			result[name] = redacted
+			continue
+		}
+		result[name] = strings.Join(values, ", ")
+	}
+	return result
+}
+
+// redactBody replaces sensitive fields in a JSON body. Bodies that are not JSON
+// are left to the subsystem's pattern masks.
+func redactBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	var decoded interface{}
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return string(body)
+	}
+
+	redactedBody, err := json.Marshal(redactValue(decoded))
+	if err != nil {
+		return string(body)
+	}
+	return string(redactedBody)
+}
+
+func redactValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for key, inner := range value {
+			if IsSensitiveField(key) {
+				value[key] = redacted
+			} else {
+				value[key] = redactValue(inner)
+			}
+		}
+		return value
+	case []interface{}:
+		for i, inner := range value {
+			value[i] = redactValue(inner)
+		}
+		return value
+	default:
+		return v
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
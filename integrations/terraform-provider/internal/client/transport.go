@@ -0,0 +1,91 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	neturl "net/url"
+	"os"
+)
+
+// TransportOptions configures TLS and proxy settings for API requests.
+type TransportOptions struct {
+	CACertFile         string
+	ClientCertFile     string
+	ClientKeyFile      string
+	ProxyURL           string
+	InsecureSkipVerify bool
+}
+
+// TransportOptionsFromEnv reads transport settings from SATURN_* environment variables.
+func TransportOptionsFromEnv() TransportOptions {
+	return TransportOptions{
+		CACertFile:         os.Getenv("SATURN_CA_CERT_FILE"),
+		ClientCertFile:     os.Getenv("SATURN_CLIENT_CERT_FILE"),
+		ClientKeyFile:      os.Getenv("SATURN_CLIENT_KEY_FILE"),
+		ProxyURL:           os.Getenv("SATURN_PROXY_URL"),
+		InsecureSkipVerify: os.Getenv("SATURN_INSECURE_SKIP_VERIFY") == "true",
+	}
+}
+
+// ConfigureTransport applies TLS and proxy settings to the client's HTTP transport.
+// Custom CAs are added to the system pool. Without a proxy URL the standard
+// HTTPS_PROXY/NO_PROXY environment variables still apply.
+func (c *Client) ConfigureTransport(opts TransportOptions) error {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: opts.InsecureSkipVerify,
+	}
+
+	if opts.CACertFile != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+
+		pem, err := os.ReadFile(opts.CACertFile)
+		if err != nil {
+			return fmt.Errorf("failed to read CA certificate: %w", err)
+		}
+
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no PEM certificates found in %s", opts.CACertFile)
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	if opts.ClientCertFile != "" || opts.ClientKeyFile != "" {
+		if opts.ClientCertFile == "" || opts.ClientKeyFile == "" {
+			return errors.New("client certificate and client key must be set together")
+		}
+
+		cert, err := tls.LoadX509KeyPair(opts.ClientCertFile, opts.ClientKeyFile)
+		if err != nil {
+			return fmt.Errorf("failed to load client certificate: %w", err)
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	if opts.ProxyURL != "" {
+		proxy, err := neturl.Parse(opts.ProxyURL)
+		if err != nil {
+			// The parse error repeats the URL, which may hold credentials
+			var urlErr *neturl.Error
+			if errors.As(err, &urlErr) {
+				err = urlErr.Err
+			}
+			return fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	c.HTTPClient.Transport = transport
+	return nil
+}
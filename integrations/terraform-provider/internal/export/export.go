@@ -0,0 +1,449 @@
+// Package export generates Terraform configuration for resources that already
+// exist in a Saturn account, so they can be adopted with import blocks.
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/saturn/terraform-provider-saturn/internal/client"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+const header = "# Generated by terraform-provider-saturn export. Review before applying.\n"
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// Run implements the "export" subcommand.
+func Run(ctx context.Context, args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	outputDir := flags.String("output", ".", "directory to write the generated .tf files to")
+	endpoint := flags.String("endpoint", "", "Saturn API endpoint (defaults to SATURN_ENDPOINT or https://saturn.co)")
+	force := flags.Bool("force", false, "overwrite existing files in the output directory")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	apiKey := os.Getenv("SATURN_API_KEY")
+	if apiKey == "" {
+		return errors.New("SATURN_API_KEY environment variable is required")
+	}
+
+	if *endpoint == "" {
+		*endpoint = os.Getenv("SATURN_ENDPOINT")
+	}
+	if *endpoint == "" {
+		*endpoint = "https://saturn.co"
+	}
+
+	c := client.NewClient(*endpoint, apiKey)
+	if err := c.ConfigureTransport(client.TransportOptionsFromEnv()); err != nil {
+		return err
+	}
+
+	e := newExporter(c)
+
+	files, err := e.generate(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(*outputDir, 0o755); err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if !*force {
+		for _, name := range names {
+			if _, err := os.Stat(filepath.Join(*outputDir, name)); err == nil {
+				return fmt.Errorf("%s already exists in %s (use -force to overwrite)", name, *outputDir)
+			}
+		}
+	}
+
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(*outputDir, name), hclwrite.Format(files[name].Bytes()), 0o644); err != nil {
+			return err
+		}
+		fmt.Fprintf(stdout, "Wrote %s\n", filepath.Join(*outputDir, name))
+	}
+
+	fmt.Fprintf(stdout, "Exported %d monitors, %d integrations, %d alert rules and %d status pages. Run \"terraform plan\" to review the imports.\n",
+		e.counts["saturn_monitor"], e.counts["saturn_integration"], e.counts["saturn_alert_rule"], e.counts["saturn_status_page"])
+
+	return nil
+}
+
+// exporter turns Saturn resources into HCL, tracking the resource address
+// assigned to every Saturn ID so references can be written as expressions.
+type exporter struct {
+	client *client.Client
+
+	used   map[string]bool
+	refs   map[string]hcl.Traversal
+	counts map[string]int
+
+	imports   *hclwrite.File
+	variables *hclwrite.File
+}
+
+func newExporter(c *client.Client) *exporter {
+	return &exporter{
+		client:    c,
+		used:      map[string]bool{},
+		refs:      map[string]hcl.Traversal{},
+		counts:    map[string]int{},
+		imports:   newFile(),
+		variables: newFile(),
+	}
+}
+
+// generate lists every supported resource and returns the files to write, keyed by file name.
+func (e *exporter) generate(ctx context.Context) (map[string]*hclwrite.File, error) {
+	monitors, err := e.client.ListMonitors(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list monitors: %w", err)
+	}
+
+	integrations, err := e.client.ListIntegrations(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list integrations: %w", err)
+	}
+
+	rules, err := e.client.ListAlertRules(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alert rules: %w", err)
+	}
+
+	pages, err := e.client.ListStatusPages(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list status pages: %w", err)
+	}
+
+	// Names are assigned up front so rules and status pages can refer to
+	// monitors and integrations regardless of output order.
+	monitorNames := make([]string, len(monitors))
+	for i, m := range monitors {
+		monitorNames[i] = e.assign("saturn_monitor", m.Name, m.ID)
+	}
+
+	integrationNames := make([]string, len(integrations))
+	for i, in := range integrations {
+		integrationNames[i] = e.assign("saturn_integration", in.Label, in.ID)
+	}
+
+	files := map[string]*hclwrite.File{}
+
+	if len(monitors) > 0 {
+		f := newFile()
+		for i := range monitors {
+			e.writeMonitor(f.Body(), monitorNames[i], &monitors[i])
+		}
+		files["monitors.tf"] = f
+	}
+
+	if len(integrations) > 0 {
+		f := newFile()
+		for i := range integrations {
+			if err := e.writeIntegration(f.Body(), integrationNames[i], &integrations[i]); err != nil {
+				return nil, err
+			}
+		}
+		files["integrations.tf"] = f
+	}
+
+	if len(rules) > 0 {
+		f := newFile()
+		for i := range rules {
+			name := e.assign("saturn_alert_rule", rules[i].Name, rules[i].ID)
+			e.writeAlertRule(f.Body(), name, &rules[i])
+		}
+		files["alert_rules.tf"] = f
+	}
+
+	if len(pages) > 0 {
+		f := newFile()
+		for i := range pages {
+			name := e.assign("saturn_status_page", pages[i].Slug, pages[i].ID)
+			e.writeStatusPage(f.Body(), name, &pages[i])
+		}
+		files["status_pages.tf"] = f
+	}
+
+	if len(e.refs) > 0 {
+		files["imports.tf"] = e.imports
+	}
+
+	if len(e.variables.Body().Blocks()) > 0 {
+		files["variables.tf"] = e.variables
+	}
+
+	return files, nil
+}
+
+// assign picks a unique resource name for a Saturn object and records its address.
+func (e *exporter) assign(resourceType, label, id string) string {
+	base := strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(label), "_"), "_")
+	if base == "" {
+		base = strings.TrimPrefix(resourceType, "saturn_")
+	}
+	if base[0] >= '0' && base[0] <= '9' {
+		base = "_" + base
+	}
+
+	name := base
+	for i := 2; e.used[resourceType+"."+name]; i++ {
+		name = fmt.Sprintf("%s_%d", base, i)
+	}
+	e.used[resourceType+"."+name] = true
+	e.counts[resourceType]++
+
+	address := hcl.Traversal{
+		hcl.TraverseRoot{Name: resourceType},
+		hcl.TraverseAttr{Name: name},
+	}
+	e.refs[id] = append(address, hcl.TraverseAttr{Name: "id"})
+
+	block := e.imports.Body().AppendNewBlock("import", nil)
+	block.Body().SetAttributeTraversal("to", address)
+	block.Body().SetAttributeValue("id", cty.StringVal(id))
+	e.imports.Body().AppendNewline()
+
+	return name
+}
+
+func (e *exporter) writeMonitor(body *hclwrite.Body, name string, m *client.Monitor) {
+	b := body.AppendNewBlock("resource", []string{"saturn_monitor", name}).Body()
+
+	b.SetAttributeValue("name", cty.StringVal(m.Name))
+	b.SetAttributeValue("schedule_type", cty.StringVal(m.ScheduleType))
+	if m.IntervalSec > 0 {
+		b.SetAttributeValue("interval_sec", cty.NumberIntVal(int64(m.IntervalSec)))
+	}
+	if m.CronExpr != "" {
+		b.SetAttributeValue("cron_expr", cty.StringVal(m.CronExpr))
+	}
+	if m.Timezone != "" {
+		b.SetAttributeValue("timezone", cty.StringVal(m.Timezone))
+	}
+	b.SetAttributeValue("grace_sec", cty.NumberIntVal(int64(m.GraceSec)))
+	if len(m.Tags) > 0 {
+		b.SetAttributeValue("tags", stringList(m.Tags))
+	}
+
+	body.AppendNewline()
+}
+
+// writeIntegration writes an integration, moving secret config values such as
+// webhook URLs into sensitive variables instead of writing them to disk.
+func (e *exporter) writeIntegration(body *hclwrite.Body, name string, in *client.Integration) error {
+	b := body.AppendNewBlock("resource", []string{"saturn_integration", name}).Body()
+
+	b.SetAttributeValue("type", cty.StringVal(in.Type))
+	b.SetAttributeValue("label", cty.StringVal(in.Label))
+
+	keys := make([]string, 0, len(in.Config))
+	for key := range in.Config {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var items []hclwrite.ObjectAttrTokens
+	for _, key := range keys {
+		var value hclwrite.Tokens
+
+		if client.IsSensitiveField(key) {
+			variable := e.variableName(name + "_" + invalidNameChars.ReplaceAllString(strings.ToLower(key), "_"))
+			v := e.variables.Body().AppendNewBlock("variable", []string{variable}).Body()
+			v.SetAttributeRaw("type", hclwrite.TokensForIdentifier("string"))
+			v.SetAttributeValue("sensitive", cty.True)
+			e.variables.Body().AppendNewline()
+
+			value = hclwrite.TokensForTraversal(hcl.Traversal{
+				hcl.TraverseRoot{Name: "var"},
+				hcl.TraverseAttr{Name: variable},
+			})
+		} else {
+			v, err := jsonValue(in.Config[key])
+			if err != nil {
+				return fmt.Errorf("integration %s: config %q: %w", in.ID, key, err)
+			}
+			value = hclwrite.TokensForValue(v)
+		}
+
+		items = append(items, hclwrite.ObjectAttrTokens{
+			Name:  hclwrite.TokensForIdentifier(key),
+			Value: value,
+		})
+	}
+	b.SetAttributeRaw("config", hclwrite.TokensForObject(items))
+
+	body.AppendNewline()
+	return nil
+}
+
+// variableName makes a variable name unique, since different config keys such
+// as apiKey and api_key normalise to the same name.
+func (e *exporter) variableName(base string) string {
+	name := base
+	for i := 2; e.used["var."+name]; i++ {
+		name = fmt.Sprintf("%s_%d", base, i)
+	}
+	e.used["var."+name] = true
+	return name
+}
+
+// jsonValue converts a decoded JSON value to the cty value Terraform would
+// read from the same JSON, keeping booleans, numbers, objects and lists intact.
+func jsonValue(v interface{}) (cty.Value, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return cty.NilVal, err
+	}
+
+	var simple ctyjson.SimpleJSONValue
+	if err := simple.UnmarshalJSON(data); err != nil {
+		return cty.NilVal, err
+	}
+	return simple.Value, nil
+}
+
+func (e *exporter) writeAlertRule(body *hclwrite.Body, name string, rule *client.AlertRule) {
+	b := body.AppendNewBlock("resource", []string{"saturn_alert_rule", name}).Body()
+
+	b.SetAttributeValue("name", cty.StringVal(rule.Name))
+	b.SetAttributeRaw("monitor_ids", e.references(rule.MonitorIDs))
+	b.SetAttributeRaw("channel_ids", e.references(rule.ChannelIDs))
+	if rule.SuppressMin > 0 {
+		b.SetAttributeValue("suppress_minutes", cty.NumberIntVal(int64(rule.SuppressMin)))
+	}
+	if rule.OnlyWhenAllFail {
+		b.SetAttributeValue("only_when_all_fail", cty.True)
+	}
+
+	body.AppendNewline()
+}
+
+func (e *exporter) writeStatusPage(body *hclwrite.Body, name string, page *client.StatusPage) {
+	b := body.AppendNewBlock("resource", []string{"saturn_status_page", name}).Body()
+
+	b.SetAttributeValue("title", cty.StringVal(page.Title))
+	b.SetAttributeValue("slug", cty.StringVal(page.Slug))
+	b.SetAttributeValue("is_public", cty.BoolVal(page.IsPublic))
+	if page.CustomDomain != "" {
+		b.SetAttributeValue("custom_domain", cty.StringVal(page.CustomDomain))
+	}
+
+	if len(page.Components) > 0 {
+		var components []hclwrite.Tokens
+		for _, raw := range page.Components {
+			component, ok := raw.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			var monitorIDs []string
+			if ids, ok := component["monitorIds"].([]interface{}); ok {
+				for _, id := range ids {
+					if s, ok := id.(string); ok {
+						monitorIDs = append(monitorIDs, s)
+					}
+				}
+			}
+
+			items := []hclwrite.ObjectAttrTokens{
+				{Name: hclwrite.TokensForIdentifier("name"), Value: hclwrite.TokensForValue(cty.StringVal(stringField(component, "name")))},
+			}
+			if description := stringField(component, "description"); description != "" {
+				items = append(items, hclwrite.ObjectAttrTokens{
+					Name:  hclwrite.TokensForIdentifier("description"),
+					Value: hclwrite.TokensForValue(cty.StringVal(description)),
+				})
+			}
+			items = append(items, hclwrite.ObjectAttrTokens{
+				Name:  hclwrite.TokensForIdentifier("monitor_ids"),
+				Value: e.references(monitorIDs),
+			})
+
+			components = append(components, hclwrite.TokensForObject(items))
+		}
+		b.SetAttributeRaw("components", hclwrite.TokensForTuple(components))
+	}
+
+	if len(page.Theme) > 0 {
+		theme := map[string]cty.Value{}
+		for attr, key := range map[string]string{
+			"primary_color":    "primaryColor",
+			"background_color": "backgroundColor",
+			"text_color":       "textColor",
+			"logo_url":         "logoUrl",
+		} {
+			if value := stringField(page.Theme, key); value != "" {
+				theme[attr] = cty.StringVal(value)
+			}
+		}
+		if len(theme) > 0 {
+			b.SetAttributeValue("theme", cty.ObjectVal(theme))
+		}
+	}
+
+	body.AppendNewline()
+}
+
+// references writes a list of Saturn IDs, using resource addresses for IDs
+// that are part of the export and literal strings for everything else.
+func (e *exporter) references(ids []string) hclwrite.Tokens {
+	elems := make([]hclwrite.Tokens, 0, len(ids))
+	for _, id := range ids {
+		if traversal, ok := e.refs[id]; ok {
+			elems = append(elems, hclwrite.TokensForTraversal(traversal))
+		} else {
+			elems = append(elems, hclwrite.TokensForValue(cty.StringVal(id)))
+		}
+	}
+	return hclwrite.TokensForTuple(elems)
+}
+
+func newFile() *hclwrite.File {
+	f := hclwrite.NewEmptyFile()
+	f.Body().AppendUnstructuredTokens(hclwrite.Tokens{
+		{Type: hclsyntax.TokenComment, Bytes: []byte(header)},
+		{Type: hclsyntax.TokenNewline, Bytes: []byte("\n")},
+	})
+	return f
+}
+
+func stringList(values []string) cty.Value {
+	if len(values) == 0 {
+		return cty.ListValEmpty(cty.String)
+	}
+	elems := make([]cty.Value, len(values))
+	for i, v := range values {
+		elems[i] = cty.StringVal(v)
+	}
+	return cty.ListVal(elems)
+}
+
+func stringField(m map[string]interface{}, key string) string {
+	s, _ := m[key].(string)
+	return s
+}
@@ -139,7 +139,7 @@ func (r *MonitorResource) Create(ctx context.Context, req resource.CreateRequest
 		monitor.Timezone = data.Timezone.ValueString()
 	}
 
-	created, err := r.client.CreateMonitor(monitor)
+	created, err := r.client.CreateMonitor(ctx, monitor)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create monitor, got error: %s", err))
 		return
@@ -159,7 +159,7 @@ func (r *MonitorResource) Read(ctx context.Context, req resource.ReadRequest, re
 		return
 	}
 
-	monitor, err := r.client.GetMonitor(data.ID.ValueString())
+	monitor, err := r.client.GetMonitor(ctx, data.ID.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read monitor, got error: %s", err))
 		return
@@ -211,7 +211,7 @@ func (r *MonitorResource) Update(ctx context.Context, req resource.UpdateRequest
 		monitor.Timezone = data.Timezone.ValueString()
 	}
 
-	updated, err := r.client.UpdateMonitor(data.ID.ValueString(), monitor)
+	updated, err := r.client.UpdateMonitor(ctx, data.ID.ValueString(), monitor)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update monitor, got error: %s", err))
 		return
@@ -231,7 +231,7 @@ func (r *MonitorResource) Delete(ctx context.Context, req resource.DeleteRequest
 		return
 	}
 
-	err := r.client.DeleteMonitor(data.ID.ValueString())
+	err := r.client.DeleteMonitor(ctx, data.ID.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete monitor, got error: %s", err))
 		return
@@ -2,6 +2,7 @@ package provider
 
 import (
 	"context"
+	neturl "net/url"
 	"os"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
@@ -9,6 +10,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/saturn/terraform-provider-saturn/internal/client"
 )
 
@@ -25,8 +27,14 @@ type SaturnProvider struct {
 
 // SaturnProviderModel describes the provider data model.
 type SaturnProviderModel struct {
-	APIKey   types.String `tfsdk:"api_key"`
-	Endpoint types.String `tfsdk:"endpoint"`
+	APIKey             types.String `tfsdk:"api_key"`
+	Endpoint           types.String `tfsdk:"endpoint"`
+	ReadCache          types.Bool   `tfsdk:"read_cache"`
+	CACertFile         types.String `tfsdk:"ca_cert_file"`
+	ClientCertFile     types.String `tfsdk:"client_cert_file"`
+	ClientKeyFile      types.String `tfsdk:"client_key_file"`
+	ProxyURL           types.String `tfsdk:"proxy_url"`
+	InsecureSkipVerify types.Bool   `tfsdk:"insecure_skip_verify"`
 }
 
 func (p *SaturnProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -47,6 +55,30 @@ func (p *SaturnProvider) Schema(ctx context.Context, req provider.SchemaRequest,
 				MarkdownDescription: "Saturn API endpoint. Defaults to https://saturn.co. Can also be set via SATURN_ENDPOINT environment variable.",
 				Optional:            true,
 			},
+			"read_cache": schema.BoolAttribute{
+				MarkdownDescription: "Prefetch each resource type with a single paginated list and serve reads from memory. Speeds up plans over many resources. Can also be set via SATURN_READ_CACHE environment variable.",
+				Optional:            true,
+			},
+			"ca_cert_file": schema.StringAttribute{
+				MarkdownDescription: "Path to a PEM bundle of additional CA certificates to trust, for self-hosted Saturn behind an internal CA. Can also be set via SATURN_CA_CERT_FILE environment variable.",
+				Optional:            true,
+			},
+			"client_cert_file": schema.StringAttribute{
+				MarkdownDescription: "Path to a PEM client certificate for mutual TLS. Requires client_key_file. Can also be set via SATURN_CLIENT_CERT_FILE environment variable.",
+				Optional:            true,
+			},
+			"client_key_file": schema.StringAttribute{
+				MarkdownDescription: "Path to the PEM private key for client_cert_file. Can also be set via SATURN_CLIENT_KEY_FILE environment variable.",
+				Optional:            true,
+			},
+			"proxy_url": schema.StringAttribute{
+				MarkdownDescription: "HTTP(S) proxy for API requests. Defaults to the HTTPS_PROXY environment variable. Can also be set via SATURN_PROXY_URL environment variable.",
+				Optional:            true,
+			},
+			"insecure_skip_verify": schema.BoolAttribute{
+				MarkdownDescription: "Skip TLS certificate verification. Only for lab environments. Can also be set via SATURN_INSECURE_SKIP_VERIFY environment variable.",
+				Optional:            true,
+			},
 		},
 	}
 }
@@ -79,6 +111,34 @@ func (p *SaturnProvider) Configure(ctx context.Context, req provider.ConfigureRe
 		endpoint = config.Endpoint.ValueString()
 	}
 
+	transport := client.TransportOptionsFromEnv()
+
+	if !config.CACertFile.IsNull() {
+		transport.CACertFile = config.CACertFile.ValueString()
+	}
+
+	if !config.ClientCertFile.IsNull() {
+		transport.ClientCertFile = config.ClientCertFile.ValueString()
+	}
+
+	if !config.ClientKeyFile.IsNull() {
+		transport.ClientKeyFile = config.ClientKeyFile.ValueString()
+	}
+
+	if !config.ProxyURL.IsNull() {
+		transport.ProxyURL = config.ProxyURL.ValueString()
+	}
+
+	if !config.InsecureSkipVerify.IsNull() {
+		transport.InsecureSkipVerify = config.InsecureSkipVerify.ValueBool()
+	}
+
+	readCache := os.Getenv("SATURN_READ_CACHE") == "true"
+
+	if !config.ReadCache.IsNull() {
+		readCache = config.ReadCache.ValueBool()
+	}
+
 	// If endpoint is not set, use default
 	if endpoint == "" {
 		endpoint = "https://saturn.co"
@@ -99,9 +159,38 @@ func (p *SaturnProvider) Configure(ctx context.Context, req provider.ConfigureRe
 		return
 	}
 
+	ctx = tflog.SetField(ctx, "saturn_endpoint", endpoint)
+	ctx = tflog.SetField(ctx, "saturn_read_cache", readCache)
+	ctx = tflog.SetField(ctx, "saturn_proxy_url", redactProxyURL(transport.ProxyURL))
+	ctx = tflog.SetField(ctx, "saturn_api_key", apiKey)
+	ctx = tflog.MaskFieldValuesWithFieldKeys(ctx, "saturn_api_key")
+
+	tflog.Debug(ctx, "Creating Saturn client")
+
 	// Create a new Saturn client using the configuration values
 	client := client.NewClient(endpoint, apiKey)
 
+	if err := client.ConfigureTransport(transport); err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid TLS or Proxy Configuration",
+			"While configuring the provider, the TLS or proxy settings "+
+				"could not be applied: "+err.Error(),
+		)
+		return
+	}
+
+	if transport.InsecureSkipVerify {
+		resp.Diagnostics.AddWarning(
+			"TLS Verification Disabled",
+			"insecure_skip_verify is set, so the Saturn API certificate is not verified. "+
+				"Use ca_cert_file instead outside of lab environments.",
+		)
+	}
+
+	if readCache {
+		client.EnableReadCache()
+	}
+
 	// Make the Saturn client available during DataSource and Resource
 	// type Configure methods.
 	resp.DataSourceData = client
@@ -131,3 +220,16 @@ func New(version string) func() provider.Provider {
 	}
 }
 
+// redactProxyURL returns a proxy URL with any password replaced, for logging.
+func redactProxyURL(raw string) string {
+	if raw == "" {
+		return ""
+	}
+
+	u, err := neturl.Parse(raw)
+	if err != nil {
+		return "(invalid)"
+	}
+
+	return u.Redacted()
+}
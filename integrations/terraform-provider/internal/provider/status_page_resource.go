@@ -0,0 +1,665 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/saturn/terraform-provider-saturn/internal/client"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &StatusPageResource{}
+var _ resource.ResourceWithImportState = &StatusPageResource{}
+
+// Default time allowed for DNS verification and certificate issuance.
+const defaultDomainTimeout = 30 * time.Minute
+
+var dnsRecordAttrTypes = map[string]attr.Type{
+	"type":  types.StringType,
+	"name":  types.StringType,
+	"value": types.StringType,
+}
+
+func NewStatusPageResource() resource.Resource {
+	return &StatusPageResource{}
+}
+
+// StatusPageResource defines the resource implementation.
+type StatusPageResource struct {
+	client *client.Client
+}
+
+// StatusPageResourceModel describes the resource data model.
+type StatusPageResourceModel struct {
+	ID                    types.String               `tfsdk:"id"`
+	Title                 types.String               `tfsdk:"title"`
+	Slug                  types.String               `tfsdk:"slug"`
+	IsPublic              types.Bool                 `tfsdk:"is_public"`
+	CustomDomain          types.String               `tfsdk:"custom_domain"`
+	WaitForDomain         types.Bool                 `tfsdk:"wait_for_domain"`
+	DomainPollIntervalSec types.Int64                `tfsdk:"domain_poll_interval_sec"`
+	DNSRecords            types.List                 `tfsdk:"dns_records"`
+	DomainReady           types.Bool                 `tfsdk:"domain_ready"`
+	Components            []StatusPageComponentModel `tfsdk:"components"`
+	Theme                 *StatusPageThemeModel      `tfsdk:"theme"`
+	Timeouts              timeouts.Value             `tfsdk:"timeouts"`
+}
+
+// StatusPageComponentModel describes a status page component.
+type StatusPageComponentModel struct {
+	Name        types.String `tfsdk:"name"`
+	Description types.String `tfsdk:"description"`
+	MonitorIDs  []string     `tfsdk:"monitor_ids"`
+}
+
+// StatusPageThemeModel describes the status page theme.
+type StatusPageThemeModel struct {
+	PrimaryColor    types.String `tfsdk:"primary_color"`
+	BackgroundColor types.String `tfsdk:"background_color"`
+	TextColor       types.String `tfsdk:"text_color"`
+	LogoURL         types.String `tfsdk:"logo_url"`
+}
+
+func (r *StatusPageResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_status_page"
+}
+
+func (r *StatusPageResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Status page resource for publishing monitor health.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Status page identifier",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"title": schema.StringAttribute{
+				MarkdownDescription: "Page title",
+				Required:            true,
+			},
+			"slug": schema.StringAttribute{
+				MarkdownDescription: "URL slug (must be unique)",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"is_public": schema.BoolAttribute{
+				MarkdownDescription: "Public visibility (default: true)",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(true),
+			},
+			"custom_domain": schema.StringAttribute{
+				MarkdownDescription: "Custom domain (e.g., status.example.com)",
+				Optional:            true,
+			},
+			"wait_for_domain": schema.BoolAttribute{
+				MarkdownDescription: "Wait during apply until the custom domain is verified and its TLS certificate is issued (default: false)",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"domain_poll_interval_sec": schema.Int64Attribute{
+				MarkdownDescription: "Seconds between custom domain checks while waiting (default: 15)",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(15),
+			},
+			"dns_records": schema.ListNestedAttribute{
+				MarkdownDescription: "DNS records to create for custom domain verification",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"type": schema.StringAttribute{
+							MarkdownDescription: "Record type: CNAME or TXT",
+							Computed:            true,
+						},
+						"name": schema.StringAttribute{
+							MarkdownDescription: "Record name",
+							Computed:            true,
+						},
+						"value": schema.StringAttribute{
+							MarkdownDescription: "Record value",
+							Computed:            true,
+						},
+					},
+				},
+				PlanModifiers: []planmodifier.List{
+					dnsRecordsPlanModifier{},
+				},
+			},
+			"domain_ready": schema.BoolAttribute{
+				MarkdownDescription: "Whether the custom domain is verified and its TLS certificate is issued",
+				Computed:            true,
+				PlanModifiers: []planmodifier.Bool{
+					domainReadyPlanModifier{},
+				},
+			},
+			"components": schema.ListNestedAttribute{
+				MarkdownDescription: "Component definitions",
+				Optional:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							MarkdownDescription: "Component name",
+							Required:            true,
+						},
+						"description": schema.StringAttribute{
+							MarkdownDescription: "Component description",
+							Optional:            true,
+						},
+						"monitor_ids": schema.ListAttribute{
+							MarkdownDescription: "Monitors backing this component",
+							Required:            true,
+							ElementType:         types.StringType,
+						},
+					},
+				},
+			},
+			"theme": schema.SingleNestedAttribute{
+				MarkdownDescription: "Theme customization",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"primary_color": schema.StringAttribute{
+						MarkdownDescription: "Primary color",
+						Optional:            true,
+					},
+					"background_color": schema.StringAttribute{
+						MarkdownDescription: "Background color",
+						Optional:            true,
+					},
+					"text_color": schema.StringAttribute{
+						MarkdownDescription: "Text color",
+						Optional:            true,
+					},
+					"logo_url": schema.StringAttribute{
+						MarkdownDescription: "Logo URL",
+						Optional:            true,
+					},
+				},
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Update: true,
+			}),
+		},
+	}
+}
+
+func (r *StatusPageResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*client.Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *client.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *StatusPageResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data StatusPageResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := data.Timeouts.Create(ctx, defaultDomainTimeout)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	created, err := r.client.CreateStatusPage(ctx, expandStatusPage(&data))
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create status page, got error: %s", err))
+		return
+	}
+
+	data.ID = types.StringValue(created.ID)
+
+	resp.Diagnostics.Append(r.applyCustomDomain(ctx, &data, createTimeout)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *StatusPageResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data StatusPageResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	page, err := r.client.GetStatusPage(ctx, data.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read status page, got error: %s", err))
+		return
+	}
+
+	data.Title = types.StringValue(page.Title)
+	data.Slug = types.StringValue(page.Slug)
+	data.IsPublic = types.BoolValue(page.IsPublic)
+
+	previousDomain := data.CustomDomain
+
+	if page.CustomDomain != "" {
+		data.CustomDomain = types.StringValue(page.CustomDomain)
+	} else {
+		data.CustomDomain = types.StringNull()
+	}
+
+	readCustomDomain(&data, previousDomain)
+
+	if data.Components != nil {
+		data.Components = flattenStatusPageComponents(page.Components)
+	}
+
+	if data.Theme != nil {
+		data.Theme = flattenStatusPageTheme(page.Theme)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *StatusPageResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data StatusPageResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateTimeout, diags := data.Timeouts.Update(ctx, defaultDomainTimeout)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	_, err := r.client.UpdateStatusPage(ctx, data.ID.ValueString(), expandStatusPage(&data))
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update status page, got error: %s", err))
+		return
+	}
+
+	resp.Diagnostics.Append(r.applyCustomDomain(ctx, &data, updateTimeout)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *StatusPageResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data StatusPageResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DeleteStatusPage(ctx, data.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete status page, got error: %s", err))
+		return
+	}
+}
+
+func (r *StatusPageResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// readCustomDomain keeps dns_records and domain_ready from state. Checking the
+// domain is a write in the API, so it only happens on create and update. If
+// the domain changed outside Terraform, the recorded values no longer apply.
+func readCustomDomain(data *StatusPageResourceModel, previousDomain types.String) {
+	if data.CustomDomain.IsNull() {
+		data.DNSRecords = types.ListNull(types.ObjectType{AttrTypes: dnsRecordAttrTypes})
+		data.DomainReady = types.BoolNull()
+		return
+	}
+
+	if !data.CustomDomain.Equal(previousDomain) {
+		data.DNSRecords = types.ListNull(types.ObjectType{AttrTypes: dnsRecordAttrTypes})
+		data.DomainReady = types.BoolValue(false)
+		return
+	}
+
+	if data.DNSRecords.IsUnknown() {
+		data.DNSRecords = types.ListNull(types.ObjectType{AttrTypes: dnsRecordAttrTypes})
+	}
+	if data.DomainReady.IsUnknown() {
+		data.DomainReady = types.BoolNull()
+	}
+}
+
+// applyCustomDomain fills in dns_records for the configured custom domain and,
+// when wait_for_domain is set, blocks until the domain is ready or timeout passes.
+// A domain that is still not ready is reported as a warning, so the status page
+// is kept and the next apply waits again.
+func (r *StatusPageResource) applyCustomDomain(ctx context.Context, data *StatusPageResourceModel, timeout time.Duration) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	data.DNSRecords = types.ListNull(types.ObjectType{AttrTypes: dnsRecordAttrTypes})
+	data.DomainReady = types.BoolNull()
+
+	if data.CustomDomain.IsNull() || data.CustomDomain.ValueString() == "" {
+		return diags
+	}
+
+	data.DomainReady = types.BoolValue(false)
+
+	domain := data.CustomDomain.ValueString()
+
+	verification, err := r.client.VerifyStatusPageDomain(ctx, data.ID.ValueString())
+	if err != nil {
+		diags.AddWarning(
+			"Unable to Check Custom Domain",
+			fmt.Sprintf("Status page %s was saved, but the DNS records for %q could not be retrieved: %s", data.ID.ValueString(), domain, err),
+		)
+		return diags
+	}
+
+	if data.WaitForDomain.ValueBool() && !verification.Ready() {
+		interval := time.Duration(data.DomainPollIntervalSec.ValueInt64()) * time.Second
+
+		waitCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		verification, err = r.waitForCustomDomain(waitCtx, data.ID.ValueString(), interval, verification)
+		if err != nil && !errors.Is(err, context.DeadlineExceeded) {
+			diags.AddError("Client Error", fmt.Sprintf("Unable to check custom domain, got error: %s", err))
+			return diags
+		}
+	}
+
+	records, recordDiags := flattenDNSRecords(domain, verification)
+	diags.Append(recordDiags...)
+	data.DNSRecords = records
+	data.DomainReady = types.BoolValue(verification.Ready())
+
+	if data.WaitForDomain.ValueBool() && !verification.Ready() {
+		diags.AddWarning(
+			"Custom Domain Not Ready",
+			fmt.Sprintf("Status page %s was saved, but custom domain %q was not ready after %s. The next apply waits again.\n\n%s",
+				data.ID.ValueString(), domain, timeout, describeDomainVerification(domain, verification)),
+		)
+	}
+
+	return diags
+}
+
+// waitForCustomDomain polls domain verification every interval until the domain
+// is ready or ctx is done, returning the last verification result it saw.
+func (r *StatusPageResource) waitForCustomDomain(ctx context.Context, id string, interval time.Duration, last *client.DomainVerification) (*client.DomainVerification, error) {
+	if interval <= 0 {
+		interval = 15 * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return last, ctx.Err()
+		case <-ticker.C:
+		}
+
+		verification, err := r.client.VerifyStatusPageDomain(ctx, id)
+		if err != nil {
+			return last, err
+		}
+
+		last = verification
+		if last.Ready() {
+			return last, nil
+		}
+	}
+}
+
+// describeDomainVerification explains what is still missing for a custom domain.
+func describeDomainVerification(domain string, v *client.DomainVerification) string {
+	var b strings.Builder
+
+	if !v.Verified {
+		b.WriteString("DNS verification has not succeeded. Create one of the following records:\n")
+		fmt.Fprintf(&b, "  CNAME %s -> %s\n", domain, v.ExpectedCNAME)
+		fmt.Fprintf(&b, "  TXT   %s -> %s\n", domain, v.ExpectedTXT)
+
+		if v.CNAMERecord != "" {
+			fmt.Fprintf(&b, "Current CNAME record: %s\n", v.CNAMERecord)
+		}
+		if v.TXTRecord != "" {
+			fmt.Fprintf(&b, "Current TXT records: %s\n", v.TXTRecord)
+		}
+		if v.Error != "" {
+			fmt.Fprintf(&b, "Last error: %s\n", v.Error)
+		}
+	} else {
+		fmt.Fprintf(&b, "DNS is verified, but the TLS certificate is not issued yet (status: %s).\n", v.TLSStatus)
+	}
+
+	b.WriteString("Re-run apply once DNS has propagated, or increase the timeouts block.")
+
+	return b.String()
+}
+
+func flattenDNSRecords(domain string, v *client.DomainVerification) (types.List, diag.Diagnostics) {
+	objectType := types.ObjectType{AttrTypes: dnsRecordAttrTypes}
+
+	var records []attr.Value
+	var diags diag.Diagnostics
+
+	if v.ExpectedCNAME != "" {
+		record, d := types.ObjectValue(dnsRecordAttrTypes, map[string]attr.Value{
+			"type":  types.StringValue("CNAME"),
+			"name":  types.StringValue(domain),
+			"value": types.StringValue(v.ExpectedCNAME),
+		})
+		diags.Append(d...)
+		records = append(records, record)
+	}
+
+	if v.ExpectedTXT != "" {
+		record, d := types.ObjectValue(dnsRecordAttrTypes, map[string]attr.Value{
+			"type":  types.StringValue("TXT"),
+			"name":  types.StringValue(domain),
+			"value": types.StringValue(v.ExpectedTXT),
+		})
+		diags.Append(d...)
+		records = append(records, record)
+	}
+
+	list, d := types.ListValue(objectType, records)
+	diags.Append(d...)
+
+	return list, diags
+}
+
+func expandStatusPage(data *StatusPageResourceModel) *client.StatusPage {
+	page := &client.StatusPage{
+		Title:    data.Title.ValueString(),
+		Slug:     data.Slug.ValueString(),
+		IsPublic: data.IsPublic.ValueBool(),
+	}
+
+	if !data.CustomDomain.IsNull() {
+		page.CustomDomain = data.CustomDomain.ValueString()
+	}
+
+	for i, component := range data.Components {
+		page.Components = append(page.Components, map[string]interface{}{
+			"id":          fmt.Sprintf("component-%d", i+1),
+			"name":        component.Name.ValueString(),
+			"description": component.Description.ValueString(),
+			"monitorIds":  component.MonitorIDs,
+		})
+	}
+
+	if data.Theme != nil {
+		page.Theme = map[string]interface{}{}
+
+		if !data.Theme.PrimaryColor.IsNull() {
+			page.Theme["primaryColor"] = data.Theme.PrimaryColor.ValueString()
+		}
+		if !data.Theme.BackgroundColor.IsNull() {
+			page.Theme["backgroundColor"] = data.Theme.BackgroundColor.ValueString()
+		}
+		if !data.Theme.TextColor.IsNull() {
+			page.Theme["textColor"] = data.Theme.TextColor.ValueString()
+		}
+		if !data.Theme.LogoURL.IsNull() {
+			page.Theme["logoUrl"] = data.Theme.LogoURL.ValueString()
+		}
+	}
+
+	return page
+}
+
+func flattenStatusPageComponents(components []interface{}) []StatusPageComponentModel {
+	result := []StatusPageComponentModel{}
+
+	for _, raw := range components {
+		component, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		model := StatusPageComponentModel{
+			Name:        optionalString(component["name"]),
+			Description: optionalString(component["description"]),
+			MonitorIDs:  []string{},
+		}
+
+		if ids, ok := component["monitorIds"].([]interface{}); ok {
+			for _, id := range ids {
+				if s, ok := id.(string); ok {
+					model.MonitorIDs = append(model.MonitorIDs, s)
+				}
+			}
+		}
+
+		result = append(result, model)
+	}
+
+	return result
+}
+
+func flattenStatusPageTheme(theme map[string]interface{}) *StatusPageThemeModel {
+	return &StatusPageThemeModel{
+		PrimaryColor:    optionalString(theme["primaryColor"]),
+		BackgroundColor: optionalString(theme["backgroundColor"]),
+		TextColor:       optionalString(theme["textColor"]),
+		LogoURL:         optionalString(theme["logoUrl"]),
+	}
+}
+
+// optionalString converts a decoded JSON value to a string attribute, null when absent or empty.
+func optionalString(v interface{}) types.String {
+	s, ok := v.(string)
+	if !ok || s == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(s)
+}
+
+// dnsRecordsPlanModifier keeps dns_records from state while custom_domain is
+// unchanged, and leaves them unknown when the domain changes.
+type dnsRecordsPlanModifier struct{}
+
+func (m dnsRecordsPlanModifier) Description(ctx context.Context) string {
+	return "Keeps DNS records from state unless the custom domain changes."
+}
+
+func (m dnsRecordsPlanModifier) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m dnsRecordsPlanModifier) PlanModifyList(ctx context.Context, req planmodifier.ListRequest, resp *planmodifier.ListResponse) {
+	if req.State.Raw.IsNull() || !req.PlanValue.IsUnknown() {
+		return
+	}
+
+	var planDomain, stateDomain types.String
+
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("custom_domain"), &planDomain)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("custom_domain"), &stateDomain)...)
+
+	if resp.Diagnostics.HasError() || planDomain.IsUnknown() {
+		return
+	}
+
+	if planDomain.Equal(stateDomain) {
+		resp.PlanValue = req.StateValue
+	}
+}
+
+// domainReadyPlanModifier keeps domain_ready from state, except while
+// wait_for_domain is set and the domain is not ready yet. Then it is left
+// unknown so the next apply runs Update, which waits for the domain again.
+type domainReadyPlanModifier struct{}
+
+func (m domainReadyPlanModifier) Description(ctx context.Context) string {
+	return "Keeps domain readiness from state unless the provider still has to wait for the custom domain."
+}
+
+func (m domainReadyPlanModifier) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m domainReadyPlanModifier) PlanModifyBool(ctx context.Context, req planmodifier.BoolRequest, resp *planmodifier.BoolResponse) {
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var planDomain, stateDomain types.String
+	var wait types.Bool
+
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("custom_domain"), &planDomain)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("custom_domain"), &stateDomain)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("wait_for_domain"), &wait)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if planDomain.IsUnknown() || !planDomain.Equal(stateDomain) ||
+		(wait.ValueBool() && !planDomain.IsNull() && !req.StateValue.ValueBool()) {
+		resp.PlanValue = types.BoolUnknown()
+		return
+	}
+
+	resp.PlanValue = req.StateValue
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"flag"
 	"log"
+	"os"
 
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/saturn/terraform-provider-saturn/internal/export"
 	"github.com/saturn/terraform-provider-saturn/internal/provider"
 )
 
@@ -29,6 +31,15 @@ var (
 )
 
 func main() {
+	// "terraform-provider-saturn export" writes HCL for an existing account
+	// instead of serving the provider.
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		if err := export.Run(context.Background(), os.Args[2:], os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	var debug bool
 
 	flag.BoolVar(&debug, "debug", false, "set to true to run the provider with support for debuggers like delve")